@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"runtime"
 	"strings"
 
+	"github.com/zscaler/terraform-provider-ztc/ztc/tokencache"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/activation"
@@ -58,7 +60,12 @@ func main() {
 		log.Printf("[INFO] Using OneAPI Client mode")
 
 		clientID := getEnvVarOrFail("ZSCALER_CLIENT_ID")
-		clientSecret := getEnvVarOrFail("ZSCALER_CLIENT_SECRET")
+		// ZSCALER_CLIENT_SECRET takes precedence over ZSCALER_PRIVATE_KEY, as in the provider config
+		clientSecret := os.Getenv("ZSCALER_CLIENT_SECRET")
+		privateKey := os.Getenv("ZSCALER_PRIVATE_KEY")
+		if clientSecret == "" && privateKey == "" {
+			log.Fatalf("[ERROR] Couldn't find environment variable ZSCALER_CLIENT_SECRET or ZSCALER_PRIVATE_KEY\n")
+		}
 		vanityDomain := getEnvVarOrFail("ZSCALER_VANITY_DOMAIN")
 		// ZSCALER_CLOUD is optional: unset or empty selects the default production cloud, matching
 		// ZIA ziaActivator and the provider config. Set it for non-production (e.g. beta).
@@ -69,13 +76,27 @@ func main() {
 
 		opts := []zscaler.ConfigSetter{
 			zscaler.WithClientID(clientID),
-			zscaler.WithClientSecret(clientSecret),
 			zscaler.WithVanityDomain(vanityDomain),
 			zscaler.WithUserAgentExtra(fmt.Sprintf("(%s %s) cli/ztcActivator", runtime.GOOS, runtime.GOARCH)),
 		}
+		secret := clientSecret
+		if secret != "" {
+			opts = append(opts, zscaler.WithClientSecret(clientSecret))
+		} else {
+			secret = privateKey
+			opts = append(opts, zscaler.WithPrivateKey(privateKey))
+		}
 		if cloud != "" {
 			opts = append(opts, zscaler.WithZscalerCloud(cloud))
 		}
+		// ZSCALER_TOKEN_CACHE_DIR shares the OneAPI token with the provider and other runs
+		if dir := os.Getenv("ZSCALER_TOKEN_CACHE_DIR"); dir != "" {
+			opts = append(opts, zscaler.WithHttpClientPtr(&http.Client{Transport: &tokencache.Transport{
+				Dir:    dir,
+				Key:    tokencache.NewKey(clientID, vanityDomain, cloud),
+				Secret: secret,
+			}}))
+		}
 
 		cfg, err := zscaler.NewConfiguration(opts...)
 		if err != nil {
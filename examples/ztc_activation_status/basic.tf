@@ -2,4 +2,7 @@ resource "ztc_activation_status" "this" {
   org_edit_status          = "org_edit_status"
   org_last_activate_status = "org_last_activate_status"
   admin_activate_status    = "admin_activate_status"
+  comment                  = "Add branch forwarding rules"
+  change_ticket            = "CHG0012345"
+  change_summary_file      = "${path.root}/ztc-activation.json"
 }
@@ -0,0 +1,15 @@
+data "ztc_edge_connector_fleet" "this" {
+  platform             = "AWS"
+  target_build_version = "24.1.0"
+}
+
+check "edge_connector_builds" {
+  assert {
+    condition     = data.ztc_edge_connector_fleet.this.vms_behind_target_count == 0
+    error_message = "${data.ztc_edge_connector_fleet.this.vms_behind_target_count} Cloud Connector VM(s) are running a build older than 24.1.0."
+  }
+}
+
+output "ztc_edge_connector_fleet" {
+  value = data.ztc_edge_connector_fleet.this.build_version_counts
+}
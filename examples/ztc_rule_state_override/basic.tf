@@ -0,0 +1,5 @@
+resource "ztc_rule_state_override" "incident" {
+  rule_type    = "forwarding"
+  name_pattern = "^Branch-"
+  state        = "DISABLED"
+}
@@ -0,0 +1,23 @@
+resource "ztc_traffic_forwarding_policy" "this" {
+  activate = true
+
+  rule {
+    name           = "ZPA_Apps"
+    forward_method = "ECZPA"
+    zpa_application_segment_groups {
+      id = [12345]
+    }
+  }
+
+  rule {
+    name           = "Direct_Internal"
+    forward_method = "DIRECT"
+    dest_addresses = ["10.0.0.0/8"]
+  }
+
+  rule {
+    name           = "ZIA_Default"
+    forward_method = "ZIA"
+    state          = "ENABLED"
+  }
+}
@@ -0,0 +1,32 @@
+resource "ztc_workload_groups" "this" {
+  name        = "Production Workloads"
+  description = "Production workloads in the shared VPC"
+  expression_json {
+    expression_containers {
+      tag_type = "ATTR"
+      operator = "AND"
+      tag_container {
+        operator = "AND"
+        tags {
+          key   = "Environment"
+          value = "production"
+        }
+        tags {
+          key   = "Team"
+          value = "network"
+        }
+      }
+    }
+    expression_containers {
+      tag_type = "VPC"
+      operator = "AND"
+      tag_container {
+        operator = "OR"
+        tags {
+          key   = "Vpc-id"
+          value = "vpc-0a1b2c3d4e5f67890"
+        }
+      }
+    }
+  }
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"github.com/zscaler/terraform-provider-ztc/ztc"
 	"github.com/zscaler/terraform-provider-ztc/ztc/common"
@@ -27,9 +28,15 @@ Version %s
 https://registry.terraform.io/providers/zscaler/ztc/latest/docs
 
 `, common.Version())
+	server, err := ztc.ProviderServer()
+	if err != nil {
+		log.Fatal(err)
+	}
 	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: ztc.ZTCProvider,
-		ProviderAddr: "registry.terraform.io/zscaler/ztc",
-		Debug:        debug,
+		// The SDKv2 provider serves resources and data sources; provider functions
+		// and ephemeral resources are muxed in next to them.
+		GRPCProviderFunc: func() tfprotov5.ProviderServer { return server },
+		ProviderAddr:     "registry.terraform.io/zscaler/ztc",
+		Debug:            debug,
 	})
 }
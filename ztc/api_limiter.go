@@ -0,0 +1,205 @@
+package ztc
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// API endpoint families. Calls in the same family share one concurrency and rate budget.
+const (
+	apiFamilyRules      = "rules"
+	apiFamilyGroups     = "groups"
+	apiFamilyGateways   = "gateways"
+	apiFamilyActivation = "activation"
+	apiFamilyOther      = "other"
+)
+
+// apiThrottleStats exports, per endpoint family, the number of calls, the total time calls
+// waited for the limiter and the number of 429 responses. They are published through
+// expvar under "ztc_api_throttle".
+var apiThrottleStats = expvar.NewMap("ztc_api_throttle")
+
+// apiEndpointFamily maps a request path to the endpoint family used for throttling.
+func apiEndpointFamily(path string) string {
+	p := strings.ToLower(path)
+	switch {
+	case strings.Contains(p, "ecadminactivatestatus"), strings.Contains(p, "activat"):
+		return apiFamilyActivation
+	case strings.Contains(p, "ecrules"), strings.Contains(p, "rules"):
+		return apiFamilyRules
+	case strings.Contains(p, "gateway"):
+		return apiFamilyGateways
+	case strings.Contains(p, "groups"), strings.Contains(p, "ecgroup"), strings.Contains(p, "networkservices"):
+		return apiFamilyGroups
+	}
+	return apiFamilyOther
+}
+
+// apiLimiter throttles API calls per endpoint family. When parallelism is set, each
+// family allows at most that many concurrent calls, and when requestsPerSecond is set,
+// each family starts at most that many calls per second. Both are unlimited when 0. A
+// 429 response pauses the whole family until Retry-After.
+type apiLimiter struct {
+	parallelism       int
+	requestsPerSecond int
+
+	mu       sync.Mutex
+	families map[string]*apiFamilyLimiter
+}
+
+type apiFamilyLimiter struct {
+	sem chan struct{}
+
+	mu           sync.Mutex
+	nextStart    time.Time
+	blockedUntil time.Time
+}
+
+func newAPILimiter(parallelism, requestsPerSecond int) *apiLimiter {
+	if parallelism < 0 {
+		parallelism = 0
+	}
+	return &apiLimiter{
+		parallelism:       parallelism,
+		requestsPerSecond: requestsPerSecond,
+		families:          map[string]*apiFamilyLimiter{},
+	}
+}
+
+func (l *apiLimiter) family(name string) *apiFamilyLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, ok := l.families[name]
+	if !ok {
+		f = &apiFamilyLimiter{}
+		if l.parallelism > 0 {
+			f.sem = make(chan struct{}, l.parallelism)
+		}
+		l.families[name] = f
+	}
+	return f
+}
+
+// acquire blocks until a call in the given family may start and returns a function that
+// releases the slot. The time spent waiting is added to the exported counters.
+func (l *apiLimiter) acquire(ctx context.Context, name string) (func(), error) {
+	f := l.family(name)
+	start := time.Now()
+
+	release := func() {}
+	if f.sem != nil {
+		select {
+		case f.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		release = func() { <-f.sem }
+	}
+
+	if wait := f.reserve(time.Now(), l.requestsPerSecond); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	waited := time.Since(start)
+	apiThrottleStats.Add(name+".calls", 1)
+	apiThrottleStats.Add(name+".throttled_ms", waited.Milliseconds())
+	if waited >= time.Second {
+		log.Printf("[DEBUG] API call in family %q was throttled for %s", name, waited)
+	}
+	return release, nil
+}
+
+// reserve returns how long a call starting at now has to wait, honouring both a pause
+// set by a 429 response and the per-second rate, and books the next start slot.
+func (f *apiFamilyLimiter) reserve(now time.Time, requestsPerSecond int) time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	startAt := now
+	if f.blockedUntil.After(startAt) {
+		startAt = f.blockedUntil
+	}
+	if requestsPerSecond > 0 {
+		if f.nextStart.After(startAt) {
+			startAt = f.nextStart
+		}
+		f.nextStart = startAt.Add(time.Second / time.Duration(requestsPerSecond))
+	}
+	return startAt.Sub(now)
+}
+
+// pause holds back new calls in the family until the given time.
+func (l *apiLimiter) pause(name string, until time.Time) {
+	f := l.family(name)
+	f.mu.Lock()
+	if until.After(f.blockedUntil) {
+		f.blockedUntil = until
+	}
+	f.mu.Unlock()
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+// It falls back to one second when the header is missing or invalid.
+func retryAfter(header string, now time.Time) time.Duration {
+	if header != "" {
+		if secs, err := strconv.Atoi(strings.TrimSpace(header)); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(header); err == nil && t.After(now) {
+			return t.Sub(now)
+		}
+	}
+	return time.Second
+}
+
+// apiLimiterTransport wraps the HTTP transport used by the SDK so that every API call
+// goes through the limiter.
+type apiLimiterTransport struct {
+	limiter *apiLimiter
+	next    http.RoundTripper
+}
+
+func (t *apiLimiterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	name := apiEndpointFamily(req.URL.Path)
+	release, err := t.limiter.acquire(req.Context(), name)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	resp, err := t.next.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		wait := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+		apiThrottleStats.Add(name+".rate_limited", 1)
+		log.Printf("[WARN] API rate limit hit for %s %s, pausing %q calls for %s", req.Method, req.URL.Path, name, wait)
+		t.limiter.pause(name, time.Now().Add(wait))
+	}
+	return resp, err
+}
+
+// newThrottledHTTPClient returns an HTTP client whose calls are throttled by the limiter
+// and then sent through next, or http.DefaultTransport when next is nil.
+func newThrottledHTTPClient(limiter *apiLimiter, next http.RoundTripper) *http.Client {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &http.Client{
+		Transport: &apiLimiterTransport{
+			limiter: limiter,
+			next:    next,
+		},
+	}
+}
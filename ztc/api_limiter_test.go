@@ -0,0 +1,115 @@
+package ztc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAPIEndpointFamily(t *testing.T) {
+	cases := map[string]string{
+		"/ztw/api/v1/ecRules/ecRdr":                  apiFamilyRules,
+		"/ztw/api/v1/ecRules/ecDns/1234":             apiFamilyRules,
+		"/ztw/api/v1/ipSourceGroups":                 apiFamilyGroups,
+		"/ztw/api/v1/ecgroup/lite":                   apiFamilyGroups,
+		"/ztw/api/v1/dnsGateways":                    apiFamilyGateways,
+		"/ztw/api/v1/gateways/1234":                  apiFamilyGateways,
+		"/ztw/api/v1/ecAdminActivateStatus/activate": apiFamilyActivation,
+		"/ztw/api/v1/provUrl":                        apiFamilyOther,
+	}
+	for path, want := range cases {
+		if got := apiEndpointFamily(path); got != want {
+			t.Errorf("apiEndpointFamily(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := retryAfter("7", now); got != 7*time.Second {
+		t.Errorf("seconds: got %s", got)
+	}
+	if got := retryAfter(now.Add(3*time.Second).Format(http.TimeFormat), now); got != 3*time.Second {
+		t.Errorf("http date: got %s", got)
+	}
+	if got := retryAfter("", now); got != time.Second {
+		t.Errorf("missing header: got %s", got)
+	}
+}
+
+func TestAPILimiterBoundsConcurrencyPerFamily(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+	}))
+	defer server.Close()
+
+	client := newThrottledHTTPClient(newAPILimiter(2, 0), nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL + "/ztw/api/v1/ecRules/ecRdr")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Fatalf("expected at most 2 concurrent calls, got %d", maxInFlight)
+	}
+}
+
+func TestAPILimiterPausesFamilyAfter429(t *testing.T) {
+	limiter := newAPILimiter(1, 0)
+	limiter.pause(apiFamilyRules, time.Now().Add(150*time.Millisecond))
+
+	start := time.Now()
+	release, err := limiter.acquire(context.Background(), apiFamilyRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if waited := time.Since(start); waited < 100*time.Millisecond {
+		t.Fatalf("expected the rules family to be paused, waited only %s", waited)
+	}
+
+	start = time.Now()
+	release, err = limiter.acquire(context.Background(), apiFamilyGroups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if waited := time.Since(start); waited > 50*time.Millisecond {
+		t.Fatalf("expected the groups family not to be paused, waited %s", waited)
+	}
+}
+
+func TestAPILimiterUnlimitedByDefault(t *testing.T) {
+	limiter := newAPILimiter(0, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	// Without parallelism, calls of one family never wait for each other
+	for i := 0; i < 50; i++ {
+		if _, err := limiter.acquire(ctx, apiFamilyRules); err != nil {
+			t.Fatalf("call %d was held back: %v", i, err)
+		}
+	}
+}
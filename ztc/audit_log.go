@@ -0,0 +1,264 @@
+package ztc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type auditContextKey struct{}
+
+// auditResource identifies the Terraform resource on whose behalf API calls are made.
+type auditResource struct {
+	Type      string
+	ID        string
+	Operation string
+}
+
+func (r auditResource) String() string {
+	if r.ID == "" {
+		return r.Type
+	}
+	return r.Type + "." + r.ID
+}
+
+// auditCall is attached to the context of a resource operation. It carries the resource
+// and counts the attempts of each request the operation makes, so that the retries of one
+// operation are not mixed with those of operations running in parallel.
+type auditCall struct {
+	resource auditResource
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func withAuditResource(ctx context.Context, r auditResource) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, &auditCall{resource: r, attempts: map[string]int{}})
+}
+
+func auditCallFromContext(ctx context.Context) (*auditCall, bool) {
+	call, ok := ctx.Value(auditContextKey{}).(*auditCall)
+	return call, ok
+}
+
+// attempt returns how many times the request identified by key was already retried in
+// this operation.
+func (c *auditCall) attempt(key string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.attempts[key]
+}
+
+// done records the outcome of an attempt: a retryable failure increments the retry count
+// of the next attempt, any other outcome resets it.
+func (c *auditCall) done(key string, retry int, retryable bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if retryable {
+		c.attempts[key] = retry + 1
+	} else {
+		delete(c.attempts, key)
+	}
+}
+
+// withAudit wraps the functions of a resource or data source so that the API calls they
+// make are attributed to it in the audit log.
+func withAudit(name string, r *schema.Resource, isDataSource bool) *schema.Resource {
+	wrap := func(operation string, f schema.CreateContextFunc) schema.CreateContextFunc {
+		if f == nil {
+			return nil
+		}
+		typeName := name
+		if isDataSource {
+			typeName = "data." + name
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			ctx = withAuditResource(ctx, auditResource{Type: typeName, ID: d.Id(), Operation: operation})
+			return f(ctx, d, meta)
+		}
+	}
+	r.CreateContext = wrap("create", r.CreateContext)
+	r.ReadContext = schema.ReadContextFunc(wrap("read", schema.CreateContextFunc(r.ReadContext)))
+	r.UpdateContext = schema.UpdateContextFunc(wrap("update", schema.CreateContextFunc(r.UpdateContext)))
+	r.DeleteContext = schema.DeleteContextFunc(wrap("delete", schema.CreateContextFunc(r.DeleteContext)))
+	return r
+}
+
+// newAuditLogger opens the audit log file and returns a sub-logger of the provider
+// logger that writes JSON lines to it at info level, whatever the provider log level.
+func newAuditLogger(base hclog.Logger, path string) (hclog.Logger, *os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening audit_log_file: %w", err)
+	}
+	logger := base.Named("audit")
+	resettable, ok := logger.(hclog.OutputResettable)
+	if !ok {
+		f.Close()
+		return nil, nil, fmt.Errorf("the provider logger cannot write the audit log")
+	}
+	if err := resettable.ResetOutput(&hclog.LoggerOptions{Output: f}); err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	logger.SetLevel(hclog.Info)
+	return logger, f, nil
+}
+
+// auditLog opens the audit log file on the first API call. The provider and its tenants
+// share one auditLog, so the file is opened once per run.
+type auditLog struct {
+	path string
+	base hclog.Logger
+
+	once   sync.Once
+	logger hclog.Logger
+	file   *os.File
+	err    error
+}
+
+func (a *auditLog) open() (hclog.Logger, error) {
+	a.once.Do(func() {
+		a.logger, a.file, a.err = newAuditLogger(a.base, a.path)
+	})
+	return a.logger, a.err
+}
+
+// close closes the audit log file if it was opened. Entries logged after close are lost.
+func (a *auditLog) close() error {
+	a.once.Do(func() {
+		a.err = fmt.Errorf("audit log is closed")
+	})
+	if a.file == nil {
+		return nil
+	}
+	return a.file.Close()
+}
+
+// auditTransport records every API call in the audit log: method, endpoint, resource,
+// status, latency, retry count and ZTW error code. Request and response bodies are
+// never logged, and sensitive query parameters are redacted. Retries are counted per
+// resource operation; calls made outside of one, such as token requests, report 0.
+type auditTransport struct {
+	logger hclog.Logger
+	next   http.RoundTripper
+}
+
+func newAuditTransport(logger hclog.Logger, next http.RoundTripper) *auditTransport {
+	return &auditTransport{logger: logger, next: next}
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	endpoint := auditEndpoint(req.URL)
+	key := req.Method + " " + endpoint
+
+	call, hasCall := auditCallFromContext(req.Context())
+	retry := 0
+	if hasCall {
+		retry = call.attempt(key)
+	}
+
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	latency := time.Since(start)
+
+	args := []interface{}{
+		"method", req.Method,
+		"endpoint", endpoint,
+		"latency_ms", latency.Milliseconds(),
+		"retry_count", retry,
+	}
+	if hasCall {
+		args = append(args, "resource", call.resource.String(), "operation", call.resource.Operation)
+	}
+
+	retryable := err != nil
+	if resp != nil {
+		args = append(args, "status", resp.StatusCode)
+		retryable = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if resp.StatusCode >= 400 {
+			if code := ztwErrorCode(resp); code != "" {
+				args = append(args, "error_code", code)
+			}
+		}
+	}
+	if err != nil {
+		args = append(args, "error", redactAuditText(err.Error()))
+	}
+
+	if hasCall {
+		call.done(key, retry, retryable)
+	}
+
+	t.logger.Info("api call", args...)
+	return resp, err
+}
+
+// auditSensitiveParams lists query parameters whose values are never written to the audit log.
+var auditSensitiveParams = []string{"token", "secret", "password", "apikey", "api_key", "key", "assertion", "code"}
+
+func auditEndpoint(u *url.URL) string {
+	query := u.Query()
+	for name := range query {
+		lower := strings.ToLower(name)
+		for _, sensitive := range auditSensitiveParams {
+			if strings.Contains(lower, sensitive) {
+				query.Set(name, "REDACTED")
+				break
+			}
+		}
+	}
+	endpoint := u.Scheme + "://" + u.Host + u.Path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return endpoint
+}
+
+// redactAuditText removes bearer tokens from free text such as transport errors.
+func redactAuditText(s string) string {
+	if i := strings.Index(strings.ToLower(s), "bearer "); i >= 0 {
+		return s[:i] + "Bearer REDACTED"
+	}
+	return s
+}
+
+// ztwErrorCode extracts the "code" field of a ZTW error response and restores the body
+// so that the SDK can still read it.
+func ztwErrorCode(resp *http.Response) string {
+	if resp.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	rest := resp.Body
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), rest), rest}
+	if err != nil {
+		return ""
+	}
+	var payload struct {
+		Code string `json:"code"`
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return ""
+	}
+	return payload.Code
+}
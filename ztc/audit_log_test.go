@@ -0,0 +1,157 @@
+package ztc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAuditTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"code":"INVALID_INPUT_ARGUMENT","message":"bad rule"}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger := testAuditLogger(t, path)
+	client := &http.Client{Transport: newAuditTransport(logger, nil)}
+
+	req, _ := http.NewRequest(http.MethodPut, server.URL+"/ztw/api/v1/ecRules/ecRdr/42?access_token=s3cr3t&page=1", nil)
+	req = req.WithContext(withAuditResource(req.Context(), auditResource{Type: "ztc_traffic_forwarding_rule", ID: "42", Operation: "update"}))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "bad rule") {
+		t.Fatalf("response body was not preserved: %q", body)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "s3cr3t") {
+		t.Fatalf("audit log contains a secret: %s", raw)
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(raw)))
+	if !scanner.Scan() {
+		t.Fatal("audit log is empty")
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+		t.Fatalf("audit log line is not JSON: %v", err)
+	}
+	for key, want := range map[string]interface{}{
+		"method":      "PUT",
+		"resource":    "ztc_traffic_forwarding_rule.42",
+		"operation":   "update",
+		"status":      float64(400),
+		"error_code":  "INVALID_INPUT_ARGUMENT",
+		"retry_count": float64(0),
+	} {
+		if entry[key] != want {
+			t.Errorf("%s = %v, want %v", key, entry[key], want)
+		}
+	}
+	if endpoint, _ := entry["endpoint"].(string); !strings.Contains(endpoint, "access_token=REDACTED") {
+		t.Errorf("endpoint was not redacted: %s", endpoint)
+	}
+}
+
+func TestAuditTransportRetryCount(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger := testAuditLogger(t, path)
+	client := &http.Client{Transport: newAuditTransport(logger, nil)}
+	ctx := withAuditResource(context.Background(), auditResource{Type: "ztc_ip_source_groups", Operation: "create"})
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/ztw/api/v1/ipSourceGroups", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	// A request of another operation does not inherit the retries
+	other := withAuditResource(context.Background(), auditResource{Type: "ztc_ip_source_groups", Operation: "read"})
+	req, _ := http.NewRequestWithContext(other, http.MethodGet, server.URL+"/ztw/api/v1/ipSourceGroups", nil)
+	if resp, err := client.Do(req); err == nil {
+		resp.Body.Close()
+	}
+
+	raw, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 audit entries, got %d", len(lines))
+	}
+	var retried map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &retried); err != nil {
+		t.Fatal(err)
+	}
+	if retried["retry_count"] != float64(1) {
+		t.Fatalf("retry_count = %v, want 1", retried["retry_count"])
+	}
+	var otherEntry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[2]), &otherEntry); err != nil {
+		t.Fatal(err)
+	}
+	if otherEntry["retry_count"] != float64(0) {
+		t.Fatalf("retry_count of another operation = %v, want 0", otherEntry["retry_count"])
+	}
+}
+
+func TestAuditLoggerIsProviderSubLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	a := &auditLog{path: path, base: NewConfig(schema.TestResourceDataRaw(t, ZTCProvider().Schema, map[string]interface{}{})).logger}
+	logger, err := a.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The provider logs errors only by default, but the audit log records every call
+	logger.Info("api call")
+	if err := a.close(); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(path)
+	var entry map[string]interface{}
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		t.Fatalf("audit log line is not JSON: %v: %s", err, raw)
+	}
+	if entry["@module"] != "ztc.audit" {
+		t.Errorf("@module = %v, want ztc.audit", entry["@module"])
+	}
+}
+
+func testAuditLogger(t *testing.T, path string) hclog.Logger {
+	t.Helper()
+	a := &auditLog{path: path, base: hclog.New(&hclog.LoggerOptions{Name: "ztc", JSONFormat: true, IndependentLevels: true})}
+	logger, err := a.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = a.close() })
+	return logger
+}
@@ -0,0 +1,133 @@
+package ztc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// changeSetEntry is a resource created, updated or deleted during the current run.
+type changeSetEntry struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// changeSet collects the resources changed by the provider during an apply, so that
+// ztc_activation_status can report what an activation contains.
+type changeSet struct {
+	mu      sync.Mutex
+	created []changeSetEntry
+	updated []changeSetEntry
+	deleted []changeSetEntry
+}
+
+func (c *changeSet) record(action string, entry changeSetEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch action {
+	case "create":
+		c.created = append(c.created, entry)
+	case "update":
+		c.updated = append(c.updated, entry)
+	case "delete":
+		c.deleted = append(c.deleted, entry)
+	}
+}
+
+// changeSetSummary is the document stored in change_summary and change_summary_file.
+type changeSetSummary struct {
+	Comment      string           `json:"comment,omitempty"`
+	ChangeTicket string           `json:"change_ticket,omitempty"`
+	Tenant       string           `json:"tenant,omitempty"`
+	ActivatedAt  string           `json:"activated_at"`
+	Created      []changeSetEntry `json:"created"`
+	Updated      []changeSetEntry `json:"updated"`
+	Deleted      []changeSetEntry `json:"deleted"`
+}
+
+// drain returns the changes recorded since the last activation and clears them, so that
+// a second activation in the same run only reports what changed after the first one.
+func (c *changeSet) drain(now time.Time) changeSetSummary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	summary := changeSetSummary{
+		ActivatedAt: now.UTC().Format(time.RFC3339),
+		Created:     sortedChangeSetEntries(c.created),
+		Updated:     sortedChangeSetEntries(c.updated),
+		Deleted:     sortedChangeSetEntries(c.deleted),
+	}
+	c.created, c.updated, c.deleted = nil, nil, nil
+	return summary
+}
+
+func sortedChangeSetEntries(entries []changeSetEntry) []changeSetEntry {
+	out := append([]changeSetEntry{}, entries...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Type != out[j].Type {
+			return out[i].Type < out[j].Type
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// writeChangeSummaryFile writes the summary document to path, replacing any previous file.
+// The summary names the changed objects and the change ticket, so the file is only
+// accessible by its owner, including when it already existed.
+func writeChangeSummaryFile(path, summary string) error {
+	if err := os.WriteFile(path, []byte(summary+"\n"), 0o600); err != nil {
+		return fmt.Errorf("writing change_summary_file: %w", err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		return fmt.Errorf("writing change_summary_file: %w", err)
+	}
+	return nil
+}
+
+func (s changeSetSummary) String() string {
+	data, _ := json.MarshalIndent(s, "", "  ")
+	return string(data)
+}
+
+// withChangeSet wraps the create, update and delete functions of a resource so that
+// every successful change is recorded in the change set of the client it used.
+func withChangeSet(name string, r *schema.Resource) *schema.Resource {
+	_, hasName := r.Schema["name"]
+	wrap := func(action string, f schema.CreateContextFunc) schema.CreateContextFunc {
+		if f == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			id := d.Id()
+			diags := f(ctx, d, meta)
+			if diags.HasError() {
+				return diags
+			}
+			client, ok := meta.(*Client)
+			if !ok || client.changes == nil {
+				return diags
+			}
+			entry := changeSetEntry{Type: name, ID: id}
+			if action != "delete" {
+				entry.ID = d.Id()
+			}
+			if hasName {
+				entry.Name, _ = d.Get("name").(string)
+			}
+			client.changes.record(action, entry)
+			return diags
+		}
+	}
+	r.CreateContext = wrap("create", r.CreateContext)
+	r.UpdateContext = schema.UpdateContextFunc(wrap("update", schema.CreateContextFunc(r.UpdateContext)))
+	r.DeleteContext = schema.DeleteContextFunc(wrap("delete", schema.CreateContextFunc(r.DeleteContext)))
+	return r
+}
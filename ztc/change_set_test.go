@@ -0,0 +1,104 @@
+package ztc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestWithChangeSet(t *testing.T) {
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Optional: true},
+		},
+		CreateContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			d.SetId("42")
+			return nil
+		},
+		UpdateContext: func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+			return diag.Errorf("update failed")
+		},
+		DeleteContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			d.SetId("")
+			return nil
+		},
+	}
+	withChangeSet("ztc_ip_source_groups", r)
+
+	client := &Client{changes: &changeSet{}}
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"name": "branch"})
+	if diags := r.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatal(diags)
+	}
+	_ = r.UpdateContext(context.Background(), d, client)
+	if diags := r.DeleteContext(context.Background(), d, client); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	summary := client.changes.drain(time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC))
+	want := changeSetEntry{Type: "ztc_ip_source_groups", ID: "42", Name: "branch"}
+	if len(summary.Created) != 1 || summary.Created[0] != want {
+		t.Errorf("created = %+v, want [%+v]", summary.Created, want)
+	}
+	if len(summary.Updated) != 0 {
+		t.Errorf("failed updates must not be recorded, got %+v", summary.Updated)
+	}
+	if len(summary.Deleted) != 1 || summary.Deleted[0] != want {
+		t.Errorf("deleted = %+v, want [%+v]", summary.Deleted, want)
+	}
+
+	if again := client.changes.drain(time.Now()); len(again.Created)+len(again.Deleted) != 0 {
+		t.Errorf("drain did not clear the change set: %+v", again)
+	}
+}
+
+func TestChangeSetSummaryString(t *testing.T) {
+	c := &changeSet{}
+	c.record("update", changeSetEntry{Type: "ztc_traffic_forwarding_rule", ID: "7"})
+	c.record("update", changeSetEntry{Type: "ztc_ip_pool_groups", ID: "3"})
+	summary := c.drain(time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC))
+	summary.ChangeTicket = "CHG0012345"
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(summary.String()), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["change_ticket"] != "CHG0012345" || decoded["activated_at"] != "2025-06-02T10:00:00Z" {
+		t.Errorf("unexpected summary %s", summary)
+	}
+	updated := decoded["updated"].([]interface{})
+	if first := updated[0].(map[string]interface{}); first["type"] != "ztc_ip_pool_groups" {
+		t.Errorf("entries are not sorted: %v", updated)
+	}
+	if created, ok := decoded["created"].([]interface{}); !ok || len(created) != 0 {
+		t.Errorf("created should be an empty list, got %v", decoded["created"])
+	}
+}
+
+func TestWriteChangeSummaryFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX permissions")
+	}
+	path := filepath.Join(t.TempDir(), "summary.json")
+	// An existing world readable file is tightened when it is replaced
+	if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeChangeSummaryFile(path, `{"changes":[]}`); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("change summary file has permissions %#o, want 0600", perm)
+	}
+}
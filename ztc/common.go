@@ -2,14 +2,19 @@ package ztc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/errorx"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/common"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/forwarding_rules"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policyresources/networkservices"
@@ -31,16 +36,85 @@ type orderWithState struct {
 	done  bool
 }
 
+// listrules is the reorder bookkeeping of one tenant. Every configured Client owns its
+// own, so that provider aliases pointing at different tenants never mix rule IDs or orders.
 type listrules struct {
-	orders      map[string]map[int]orderWithState
-	orderer     map[string]int
-	reorderDone map[string]chan struct{}
+	orders  map[string]map[int]orderWithState
+	orderer map[string]int
+	cycles  map[string]*reorderCycle
 	sync.Mutex
+
+	// startingOrders is the order new rules are created at, by resource type
+	startingOrders map[string]int
+	startingMu     sync.Mutex
+
+	// slots caches the live rule slots by resource type, so that a refresh or a reorder
+	// cycle lists the rules once. Creating, updating or deleting a rule invalidates it.
+	slots   map[string][]ruleSlot
+	slotsMu sync.Mutex
+}
+
+func newListRules() *listrules {
+	return &listrules{
+		orders:         make(map[string]map[int]orderWithState),
+		cycles:         make(map[string]*reorderCycle),
+		startingOrders: make(map[string]int),
+		slots:          make(map[string][]ruleSlot),
+	}
+}
+
+// ruleSlots returns the cached live rule slots of resourceType, listing them with list
+// when they are not cached.
+func (rules *listrules) ruleSlots(ctx context.Context, resourceType string, list func(ctx context.Context) ([]ruleSlot, error)) ([]ruleSlot, error) {
+	rules.slotsMu.Lock()
+	defer rules.slotsMu.Unlock()
+	if slots, ok := rules.slots[resourceType]; ok {
+		return slots, nil
+	}
+	slots, err := list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules.setRuleSlotsLocked(resourceType, slots)
+	return slots, nil
+}
+
+func (rules *listrules) setRuleSlots(resourceType string, slots []ruleSlot) {
+	rules.slotsMu.Lock()
+	defer rules.slotsMu.Unlock()
+	rules.setRuleSlotsLocked(resourceType, slots)
+}
+
+func (rules *listrules) setRuleSlotsLocked(resourceType string, slots []ruleSlot) {
+	if rules.slots == nil {
+		rules.slots = map[string][]ruleSlot{}
+	}
+	rules.slots[resourceType] = slots
 }
 
-var rules = listrules{
-	orders:      make(map[string]map[int]orderWithState),
-	reorderDone: make(map[string]chan struct{}),
+// invalidateRuleSlots drops the cached rule slots of resourceType after a rule changed.
+func (rules *listrules) invalidateRuleSlots(resourceType string) {
+	rules.slotsMu.Lock()
+	defer rules.slotsMu.Unlock()
+	delete(rules.slots, resourceType)
+}
+
+// startingOrder returns the order at which new rules of resourceType are created. It is
+// computed once per tenant from the highest order returned by highestOrder.
+func (rules *listrules) startingOrder(resourceType string, highestOrder func() int) int {
+	rules.startingMu.Lock()
+	defer rules.startingMu.Unlock()
+	if rules.startingOrders == nil {
+		rules.startingOrders = map[string]int{}
+	}
+	if rules.startingOrders[resourceType] == 0 {
+		order := highestOrder()
+		if order == 0 {
+			order = 1
+		}
+		rules.startingOrders[resourceType] = order
+	}
+	return rules.startingOrders[resourceType]
 }
 
 type RuleIDOrderPair struct {
@@ -59,66 +133,113 @@ func (p RuleIDOrderPairList) Less(i, j int) bool {
 }
 func (p RuleIDOrderPairList) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
 
+// defaultResourceTimeout bounds each create, read, update and delete call of a resource,
+// including the retries of the API client, unless the configuration sets a timeouts block.
+const defaultResourceTimeout = 20 * time.Minute
+
+// resourceTimeouts returns the timeouts of resources that don't need longer defaults.
+func resourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultResourceTimeout),
+		Read:   schema.DefaultTimeout(defaultResourceTimeout),
+		Update: schema.DefaultTimeout(defaultResourceTimeout),
+		Delete: schema.DefaultTimeout(defaultResourceTimeout),
+	}
+}
+
 // reorderTickInterval controls how often the reorder ticker fires.
 // Default is 30 seconds. Tests can override this to speed up reorder cycles.
 var reorderTickInterval = 30 * time.Second
 
-func reorderAll(resourceType string, getCount func() (int, error), updateOrder func(id int, order OrderRule) error, beforeReorder func()) {
+// reorderCycle is one run of the reorder goroutine of a resource type. err is set when
+// the whole cycle failed and ruleErrs holds the rules whose last reorder attempt failed;
+// both are only read once done is closed.
+type reorderCycle struct {
+	done     chan struct{}
+	err      error
+	ruleErrs map[int]error
+}
+
+func newReorderCycle() *reorderCycle {
+	return &reorderCycle{done: make(chan struct{}), ruleErrs: map[int]error{}}
+}
+
+func (c *reorderCycle) finished() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// reorderAll moves the registered rules of resourceType to their intended order once all
+// of them are created or updated, and returns when the set has been stable for 3 ticks or
+// ctx is done.
+func (rules *listrules) reorderAll(ctx context.Context, cycle *reorderCycle, resourceType string, getCount func(ctx context.Context) (int, error), updateOrder func(ctx context.Context, id int, order OrderRule) error, beforeReorder func()) error {
 	ticker := time.NewTicker(reorderTickInterval)
 	defer ticker.Stop()
 	lastReorderedSize := 0
 	stableAfterReorder := 0
 	for {
 		select {
+		case <-ctx.Done():
+			return fmt.Errorf("reorder of %s rules interrupted: %w", resourceType, ctx.Err())
 		case <-ticker.C:
-			rules.Lock()
-			size := len(rules.orders[resourceType])
-			allDone := true
-			for _, v := range rules.orders[resourceType] {
-				if !v.done {
-					allDone = false
-					break
-				}
+		}
+
+		rules.Lock()
+		size := len(rules.orders[resourceType])
+		allDone := true
+		for _, v := range rules.orders[resourceType] {
+			if !v.done {
+				allDone = false
+				break
 			}
+		}
 
-			if allDone && size > 0 {
-				if size != lastReorderedSize {
-					// New rules registered since last reorder — reorder with the full set
-					count, _ := getCount()
-					sorted := sortOrders(rules.orders[resourceType])
-					log.Printf("[INFO] sorting filtering rule after tick; sorted:%v (size changed from %d to %d)", sorted, lastReorderedSize, size)
-					if beforeReorder != nil {
-						beforeReorder()
+		if allDone && size > 0 {
+			if size != lastReorderedSize {
+				// New rules registered since last reorder — reorder with the full set
+				count, err := getCount(ctx)
+				if err != nil {
+					rules.Unlock()
+					return fmt.Errorf("couldn't count the %s rules to reorder: %w", resourceType, err)
+				}
+				sorted := sortOrders(rules.orders[resourceType])
+				log.Printf("[INFO] sorting filtering rule after tick; sorted:%v (size changed from %d to %d)", sorted, lastReorderedSize, size)
+				if beforeReorder != nil {
+					beforeReorder()
+				}
+				for _, v := range sorted {
+					if v.Order.Order > count {
+						continue
 					}
-					for _, v := range sorted {
-						if v.Order.Order <= count {
-							if err := updateOrder(v.ID, v.Order); err != nil {
-								log.Printf("[ERROR] couldn't reorder the rule after tick, the order may not have taken place: %v\n", err)
-							}
-						}
+					if err := updateOrder(ctx, v.ID, v.Order); err != nil {
+						cycle.ruleErrs[v.ID] = fmt.Errorf("couldn't move rule %d to order %d, rank %d: %w", v.ID, v.Order.Order, v.Order.Rank, err)
+					} else {
+						delete(cycle.ruleErrs, v.ID)
 					}
-					lastReorderedSize = size
-					stableAfterReorder = 0
-				} else {
-					// Same size as last reorder — count towards stability
-					stableAfterReorder++
-					log.Printf("[INFO] reorder stable tick %d/3 for %s (%d rules)", stableAfterReorder, resourceType, size)
 				}
+				lastReorderedSize = size
+				stableAfterReorder = 0
+			} else {
+				// Same size as last reorder — count towards stability
+				stableAfterReorder++
+				log.Printf("[INFO] reorder stable tick %d/3 for %s (%d rules)", stableAfterReorder, resourceType, size)
+			}
 
-				if stableAfterReorder >= 3 {
-					log.Printf("[INFO] reorder complete for %s: %d rules, stable for 3 ticks", resourceType, size)
-					rules.Unlock()
-					return
-				}
+			if stableAfterReorder >= 3 {
+				log.Printf("[INFO] reorder complete for %s: %d rules, stable for 3 ticks", resourceType, size)
+				rules.Unlock()
+				return nil
 			}
-			rules.Unlock()
-		default:
-			time.Sleep(reorderTickInterval / 2)
 		}
+		rules.Unlock()
 	}
 }
 
-func markOrderRuleAsDone(id int, resourceType string) {
+func (rules *listrules) markOrderRuleAsDone(id int, resourceType string) {
 	rules.Lock()
 	r := rules.orders[resourceType][id]
 	r.done = true
@@ -131,63 +252,512 @@ type OrderRule struct {
 	Rank  int
 }
 
-func reorderWithBeforeReorder(order OrderRule, id int, resourceType string, getCount func() (int, error), updateOrder func(id int, order OrderRule) error, beforeReorder func()) {
+// reorderWithBeforeReorder registers the intended order of rule id. The first rule of a
+// cycle starts the reorder goroutine, which runs with that rule's context: cancelling it
+// or reaching its timeout stops the cycle and fails every rule waiting on it.
+func (rules *listrules) reorderWithBeforeReorder(ctx context.Context, order OrderRule, id int, resourceType string, getCount func(ctx context.Context) (int, error), updateOrder func(ctx context.Context, id int, order OrderRule) error, beforeReorder func()) {
 	rules.Lock()
 	shouldCallReorder := false
 	if rules.orderer == nil {
 		rules.orderer = map[string]int{}
-		rules.reorderDone = map[string]chan struct{}{}
+		rules.cycles = map[string]*reorderCycle{}
 	}
 	if rules.orders == nil {
 		rules.orders = map[string]map[int]orderWithState{}
 	}
 	if _, ok := rules.orderer[resourceType]; ok {
 		// Check if the previous reorder goroutine has already finished
-		select {
-		case <-rules.reorderDone[resourceType]:
-			// Channel is closed — previous reorder finished.
-			// Start a new reorder cycle for late-arriving rules.
+		if rules.cycles[resourceType].finished() {
+			// Previous reorder finished. Start a new reorder cycle for late-arriving rules.
 			log.Printf("[INFO] previous reorder for %s completed, starting new cycle for rule:%d", resourceType, id)
-			rules.reorderDone[resourceType] = make(chan struct{})
+			rules.cycles[resourceType] = newReorderCycle()
 			shouldCallReorder = true
-		default:
-			// Reorder goroutine still running — just register, don't start another
-			shouldCallReorder = false
 		}
+		// Otherwise the reorder goroutine is still running — just register, don't start another
 	} else {
 		rules.orderer[resourceType] = id
 		shouldCallReorder = true
-		rules.reorderDone[resourceType] = make(chan struct{})
+		rules.cycles[resourceType] = newReorderCycle()
 	}
 	if rules.orders[resourceType] == nil {
 		rules.orders[resourceType] = map[int]orderWithState{}
 	}
 	rules.orders[resourceType][id] = orderWithState{order, false}
+	cycle := rules.cycles[resourceType]
 	rules.Unlock()
 	if shouldCallReorder {
 		log.Printf("[INFO] starting to reorder the rules, delegating to rule:%d, order:%d", id, order)
-		doneCh := rules.reorderDone[resourceType]
 		go func() {
-			reorderAll(resourceType, getCount, updateOrder, beforeReorder)
-			close(doneCh)
+			err := rules.reorderAll(ctx, cycle, resourceType, getCount, updateOrder, beforeReorder)
+			rules.Lock()
+			cycle.err = err
+			rules.Unlock()
+			close(cycle.done)
 		}()
 	}
 }
 
 // waitForReorder blocks until the reorder goroutine for the given resource type
 // has completed. All rules should call this after markOrderRuleAsDone and before
-// reading the resource, to ensure the final order is reflected in the API.
-func waitForReorder(resourceType string) {
+// reading the resource, to ensure the final order is reflected in the API. It returns
+// the error of the cycle or of rule id, or ctx's error when the caller gives up waiting.
+func (rules *listrules) waitForReorder(ctx context.Context, resourceType string, id int) error {
 	rules.Lock()
-	ch := rules.reorderDone[resourceType]
+	cycle := rules.cycles[resourceType]
 	rules.Unlock()
-	if ch != nil {
-		<-ch
+	if cycle == nil {
+		return nil
+	}
+	select {
+	case <-cycle.done:
+	case <-ctx.Done():
+		return fmt.Errorf("stopped waiting for the reorder of %s rules: %w", resourceType, ctx.Err())
+	}
+	rules.Lock()
+	defer rules.Unlock()
+	if cycle.err != nil {
+		return cycle.err
 	}
+	return cycle.ruleErrs[id]
+}
+
+func (rules *listrules) reorder(ctx context.Context, order OrderRule, id int, resourceType string, getCount func(ctx context.Context) (int, error), updateOrder func(ctx context.Context, id int, order OrderRule) error) {
+	rules.reorderWithBeforeReorder(ctx, order, id, resourceType, getCount, updateOrder, nil)
+}
+
+// ruleAnchor is the part of a live rule needed to resolve relative placement.
+type ruleAnchor struct {
+	ID    int
+	Name  string
+	Order int
+	Rank  int
+}
+
+// resolveRelativeRuleOrder returns the order and rank that place the rule selfID directly
+// before (or after) the anchor rule, referenced by ID or by name, in the live list. The
+// order is computed as if selfID were first removed from the list, so the result is the
+// same whether the rule is being created or moved. The anchor's rank is used unless
+// configuredRank is set.
+func resolveRelativeRuleOrder(anchor string, after bool, selfID int, configuredRank *int, list []ruleAnchor) (OrderRule, error) {
+	var target *ruleAnchor
+	if anchorID, err := strconv.Atoi(anchor); err == nil {
+		for i := range list {
+			if list[i].ID == anchorID {
+				target = &list[i]
+				break
+			}
+		}
+	}
+	if target == nil {
+		for i := range list {
+			if list[i].Name == anchor {
+				target = &list[i]
+				break
+			}
+		}
+	}
+	if target == nil {
+		return OrderRule{}, fmt.Errorf("anchor rule %q not found; reference an existing rule by ID or name, or use the anchor resource's id so it is created first", anchor)
+	}
+	if target.ID == selfID {
+		return OrderRule{}, fmt.Errorf("rule %d cannot be placed relative to itself", selfID)
+	}
+
+	order := target.Order
+	for _, r := range list {
+		if r.ID == selfID && r.Order < target.Order {
+			// Removing the rule from above the anchor shifts the anchor up by one
+			order--
+			break
+		}
+	}
+	if after {
+		order++
+	}
+
+	rank := target.Rank
+	if configuredRank != nil {
+		rank = *configuredRank
+	}
+	return OrderRule{Order: order, Rank: rank}, nil
+}
+
+// recordResolvedRuleOrder stores the order and rank resolved from a relative placement
+// as the intended position of the rule.
+func recordResolvedRuleOrder(d *schema.ResourceData, order OrderRule) {
+	_ = d.Set("intended_order", order.Order)
+	_ = d.Set("intended_rank", order.Rank)
+}
+
+// predefinedRuleNames lists, per reorder resource type, the rules the API creates on
+// every tenant. They cannot be deleted and do not take part in user-controlled ordering.
+// The forwarding and DNS rule APIs do not flag their predefined rules, so they are
+// recognized by the names the API gives them, and user rules cannot be created with these
+// names. Log rules carry the defaultRule flag instead.
+var predefinedRuleNames = map[string][]string{
+	"forwarding_control_rule": {
+		"ZPA Forwarding Rule",
+		"Direct rule for Zscaler Cloud Endpoints",
+		"Direct rule for WAN Destinations Group",
+		"Direct rule for LAN Destinations Group",
+		"Client Connector to ZPA",
+		"ZPA Pool For Stray Traffic",
+	},
+	"traffic_forwarding_dns_rule": {
+		"ZPA Resolver",
+		"Redirect Resolution of Zscaler Domains to WAN CTR",
+	},
+}
+
+func isPredefinedRuleName(resourceType, name string) bool {
+	for _, n := range predefinedRuleNames[resourceType] {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// reservedRuleNameError returns an error when a new rule would take the name of a
+// predefined rule, as it would be mistaken for it.
+func reservedRuleNameError(resourceType, name string) error {
+	if isPredefinedRuleName(resourceType, name) {
+		return fmt.Errorf("'%s' is the name of a predefined rule; import the predefined rule instead or choose another name", name)
+	}
+	return nil
+}
+
+// predefinedRuleDeleteDiagnostics returns the warning emitted when Terraform is asked to
+// destroy a predefined rule. The rule is only removed from state.
+func predefinedRuleDeleteDiagnostics(resourceName string, id int, err error) diag.Diagnostics {
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  "predefined rule was not deleted",
+			Detail:   fmt.Sprintf("%s %d: %v. The rule has been removed from the Terraform state but still exists in the ZTC portal.", resourceName, id, err),
+		},
+	}
+}
+
+// errRuleNotFound is returned when a rule looked up in the rule list does not exist.
+var errRuleNotFound = errors.New("not found")
+
+// isRuleNotFound reports whether reading a rule failed because it no longer exists.
+func isRuleNotFound(err error) bool {
+	if respErr, ok := err.(*errorx.ErrorResponse); ok && respErr.IsObjectNotFound() {
+		return true
+	}
+	return errors.Is(err, errRuleNotFound)
+}
+
+// predefinedRuleDeleteCheck returns why a rule about to be deleted must be kept because
+// it is predefined. validate checks the live rule; when the rule couldn't be read, with
+// readErr, the predefined flag stored in the state decides instead.
+func predefinedRuleDeleteCheck(d *schema.ResourceData, readErr error, validate func() error) error {
+	if readErr == nil {
+		return validate()
+	}
+	if predefined, _ := d.Get("predefined").(bool); predefined {
+		return fmt.Errorf("the state marks the rule as predefined and it could not be read: %v", readErr)
+	}
+	return nil
+}
+
+// ruleSlot is the position of a live rule in the API order.
+type ruleSlot struct {
+	ID         int
+	Order      int
+	Predefined bool
 }
 
-func reorder(order OrderRule, id int, resourceType string, getCount func() (int, error), updateOrder func(id int, order OrderRule) error) {
-	reorderWithBeforeReorder(order, id, resourceType, getCount, updateOrder, nil)
+// ruleOrderSlots maps between user orders, which only count the rules whose order is
+// controlled by the user, and API orders, which also count predefined rules. Predefined
+// rules keep the API orders they hold and user rules fill the orders around them.
+type ruleOrderSlots struct {
+	predefined []int
+	userRules  int
+}
+
+func newRuleOrderSlots(rules []ruleSlot) ruleOrderSlots {
+	var s ruleOrderSlots
+	for _, r := range rules {
+		switch {
+		case !r.Predefined:
+			s.userRules++
+		case r.Order > 0:
+			s.predefined = append(s.predefined, r.Order)
+		}
+	}
+	sort.Ints(s.predefined)
+	return s
+}
+
+// apiOrder returns the API order of the rule at userOrder among the user rules.
+func (s ruleOrderSlots) apiOrder(userOrder int) int {
+	if userOrder <= 0 {
+		return userOrder
+	}
+	order := userOrder
+	for _, p := range s.predefined {
+		if p > order {
+			break
+		}
+		order++
+	}
+	return order
+}
+
+// userOrder returns the user order of the user rule at apiOrder.
+func (s ruleOrderSlots) userOrder(apiOrder int) int {
+	order := apiOrder
+	for _, p := range s.predefined {
+		if p >= apiOrder {
+			break
+		}
+		order--
+	}
+	return order
+}
+
+// interleaved reports whether predefined rules hold API orders between the first and the
+// last of userRules user rules, where placing user rules can move them.
+func (s ruleOrderSlots) interleaved(userRules int) bool {
+	first, last := s.apiOrder(1), s.apiOrder(userRules)
+	for _, p := range s.predefined {
+		if p > first && p < last {
+			return true
+		}
+	}
+	return false
+}
+
+// moveRuleSlot returns the slots after rule id moved to apiOrder. The rules with a
+// positive order are renumbered from 1 the way the API does, so the rules between the
+// old and the new order, predefined ones included, shift by one.
+func moveRuleSlot(slots []ruleSlot, id, apiOrder int) []ruleSlot {
+	var moved *ruleSlot
+	ordered := make([]ruleSlot, 0, len(slots))
+	result := make([]ruleSlot, 0, len(slots))
+	for i := range slots {
+		switch {
+		case slots[i].ID == id:
+			moved = &slots[i]
+		case slots[i].Order > 0:
+			ordered = append(ordered, slots[i])
+		default:
+			result = append(result, slots[i])
+		}
+	}
+	if moved == nil {
+		return slots
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Order < ordered[j].Order })
+	at := apiOrder - 1
+	if at < 0 {
+		at = 0
+	}
+	if at > len(ordered) {
+		at = len(ordered)
+	}
+	ordered = append(ordered[:at], append([]ruleSlot{*moved}, ordered[at:]...)...)
+	for i := range ordered {
+		ordered[i].Order = i + 1
+	}
+	return append(result, ordered...)
+}
+
+// userOrderReorder returns the getCount and updateOrder functions passed to reorder for
+// rules of resourceType ordered by user order. list returns the live rules, and move
+// moves a rule to an API order and rank. The rules are listed once per reorder pass and
+// the cached slots follow the moves, so a pass makes one list call.
+func userOrderReorder(rules *listrules, resourceType string, list func(ctx context.Context) ([]ruleSlot, error), move func(ctx context.Context, id int, order OrderRule) error) (func(ctx context.Context) (int, error), func(ctx context.Context, id int, order OrderRule) error) {
+	getCount := func(ctx context.Context) (int, error) {
+		slots, err := list(ctx)
+		if err != nil {
+			return 0, err
+		}
+		rules.setRuleSlots(resourceType, slots)
+		return newRuleOrderSlots(slots).userRules, nil
+	}
+	updateOrder := func(ctx context.Context, id int, order OrderRule) error {
+		slots, err := rules.ruleSlots(ctx, resourceType, list)
+		if err != nil {
+			return err
+		}
+		order.Order = newRuleOrderSlots(slots).apiOrder(order.Order)
+		if err := move(ctx, id, order); err != nil {
+			rules.invalidateRuleSlots(resourceType)
+			return err
+		}
+		// Moving a rule can shift the predefined rules, which the cached slots follow
+		rules.setRuleSlots(resourceType, moveRuleSlot(slots, id, order.Order))
+		return nil
+	}
+	return getCount, updateOrder
+}
+
+// ruleOrderDriftSchema returns the computed attributes shared by the ordered rule
+// resources to report where a rule was requested versus where the API placed it.
+func ruleOrderDriftSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"intended_order": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The order requested in the configuration on the last create or update",
+		},
+		"intended_rank": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The rank requested in the configuration on the last create or update. 0 means no rank was requested",
+		},
+		"effective_order": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The order at which the API actually placed the rule, among the user-defined rules",
+		},
+		"effective_rank": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The rank the API actually assigned to the rule",
+		},
+		"order_drift": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "True when the effective order or rank differs from the intended order or rank",
+		},
+	}
+}
+
+// intendedRuleOrder returns the order and rank exactly as written in the configuration,
+// before expand functions apply fallbacks such as replacing order 0 with 1.
+func intendedRuleOrder(d *schema.ResourceData) (int, int) {
+	order, _ := d.Get("order").(int)
+	rank, _ := d.Get("rank").(int)
+
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() || !raw.Type().IsObjectType() {
+		return order, rank
+	}
+	if v := rawConfigInt(raw, "order"); v != nil {
+		order = *v
+	}
+	if v := rawConfigInt(raw, "rank"); v != nil {
+		rank = *v
+	} else {
+		rank = 0
+	}
+	return order, rank
+}
+
+func rawConfigInt(raw cty.Value, attr string) *int {
+	if !raw.Type().HasAttribute(attr) {
+		return nil
+	}
+	v := raw.GetAttr(attr)
+	if v.IsNull() || !v.IsKnown() || v.Type() != cty.Number {
+		return nil
+	}
+	i, _ := v.AsBigFloat().Int64()
+	n := int(i)
+	return &n
+}
+
+// rawConfigLength returns the number of elements configured for a list or set attribute.
+// ok is false when the attribute is not known yet at plan time.
+func rawConfigLength(raw cty.Value, attr string) (n int, ok bool) {
+	if raw.IsNull() || !raw.IsKnown() || !raw.Type().IsObjectType() || !raw.Type().HasAttribute(attr) {
+		return 0, false
+	}
+	v := raw.GetAttr(attr)
+	if v.IsNull() {
+		return 0, true
+	}
+	if !v.IsKnown() || !v.CanIterateElements() {
+		return 0, false
+	}
+	return v.LengthInt(), true
+}
+
+// ruleMetadataAttributes are the rule attributes that never affect the position of a rule.
+var ruleMetadataAttributes = []string{"name", "description", "state"}
+
+// isRuleMetadataOnlyUpdate reports whether an update only changes the state or metadata of
+// a rule, in which case the rule keeps its current position and no reorder is needed.
+func isRuleMetadataOnlyUpdate(d *schema.ResourceData) bool {
+	return !d.HasChangesExcept(ruleMetadataAttributes...)
+}
+
+// recordIntendedRuleOrder stores the configured order and rank so that later reads can
+// compare them against the position assigned by the API.
+func recordIntendedRuleOrder(d *schema.ResourceData) {
+	order, rank := intendedRuleOrder(d)
+	_ = d.Set("intended_order", order)
+	_ = d.Set("intended_rank", rank)
+}
+
+// setRuleOrderDrift records the effective order and rank returned by the API. Rules that
+// were imported have never been read nor created by Terraform, so the effective values
+// are adopted as intended.
+func setRuleOrderDrift(d *schema.ResourceData, effectiveOrder, effectiveRank int) {
+	neverRead := d.Get("effective_order").(int) == 0 && d.Get("intended_order").(int) == 0
+	if neverRead && !d.IsNewResource() {
+		_ = d.Set("intended_order", effectiveOrder)
+		_ = d.Set("intended_rank", effectiveRank)
+	}
+	intendedOrder := d.Get("intended_order").(int)
+	intendedRank := d.Get("intended_rank").(int)
+
+	_ = d.Set("effective_order", effectiveOrder)
+	_ = d.Set("effective_rank", effectiveRank)
+	_ = d.Set("order_drift", ruleOrderDrifted(intendedOrder, intendedRank, effectiveOrder, effectiveRank))
+}
+
+func ruleOrderDrifted(intendedOrder, intendedRank, effectiveOrder, effectiveRank int) bool {
+	if intendedOrder != effectiveOrder {
+		return true
+	}
+	return intendedRank != 0 && intendedRank != effectiveRank
+}
+
+// ruleOrderDriftDiagnostics returns a warning when the API placed the rule somewhere
+// other than the order and rank requested in the configuration.
+func ruleOrderDriftDiagnostics(d *schema.ResourceData, resourceName string) diag.Diagnostics {
+	if !d.Get("order_drift").(bool) {
+		return nil
+	}
+	intendedOrder := d.Get("intended_order").(int)
+	intendedRank := d.Get("intended_rank").(int)
+	effectiveOrder := d.Get("effective_order").(int)
+	effectiveRank := d.Get("effective_rank").(int)
+
+	detail := fmt.Sprintf("%s %q (ID %s) was requested at order %d", resourceName, d.Get("name").(string), d.Id(), intendedOrder)
+	if intendedRank != 0 {
+		detail += fmt.Sprintf(" with rank %d", intendedRank)
+	}
+	detail += fmt.Sprintf(", but the API placed it at order %d with rank %d.", effectiveOrder, effectiveRank)
+	if intendedOrder <= 0 {
+		detail += " Orders lower than 1 are not accepted and fall back to 1."
+	} else {
+		detail += " Orders greater than the number of existing rules, and orders not allowed for the requested rank, are not applied."
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  "rule order differs from configuration",
+			Detail:   detail,
+		},
+	}
+}
+
+// readRuleWithOrderDrift reads the rule and appends a warning when the API placed it
+// somewhere other than the configured order and rank.
+func readRuleWithOrderDrift(ctx context.Context, d *schema.ResourceData, meta interface{}, resourceName string, read schema.ReadContextFunc) diag.Diagnostics {
+	diags := read(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, ruleOrderDriftDiagnostics(d, resourceName)...)
 }
 
 func flattenCommonIDNameExternalID(gp *common.CommonIDNameExternalID) []map[string]interface{} {
@@ -919,6 +1489,12 @@ func expandIDNameExtensionsSet(d *schema.ResourceData, key string) []common.IDNa
 	if !ok {
 		return []common.IDNameExtensions{}
 	}
+	return expandIDNameExtensionsFromSet(setInterface)
+}
+
+// expandIDNameExtensionsFromSet is the value-based counterpart of expandIDNameExtensionsSet,
+// used for attributes nested inside blocks where no *schema.ResourceData is available.
+func expandIDNameExtensionsFromSet(setInterface interface{}) []common.IDNameExtensions {
 	set, ok := setInterface.(*schema.Set)
 	if !ok {
 		return []common.IDNameExtensions{}
@@ -958,8 +1534,14 @@ func expandZPAApplicationSegmentSet(d *schema.ResourceData, key string) []common
 	if !ok {
 		return []common.ZPAApplicationSegments{}
 	}
+	return expandZPAApplicationSegmentsFromSet(setInterface)
+}
 
-	set := setInterface.(*schema.Set)
+func expandZPAApplicationSegmentsFromSet(setInterface interface{}) []common.ZPAApplicationSegments {
+	set, ok := setInterface.(*schema.Set)
+	if !ok {
+		return []common.ZPAApplicationSegments{}
+	}
 	var result []common.ZPAApplicationSegments
 	for _, item := range set.List() {
 		itemMap, _ := item.(map[string]interface{})
@@ -980,8 +1562,14 @@ func expandZPAApplicationSegmentGroupSet(d *schema.ResourceData, key string) []c
 	if !ok {
 		return []common.ZPAApplicationSegmentGroups{}
 	}
+	return expandZPAApplicationSegmentGroupsFromSet(setInterface)
+}
 
-	set := setInterface.(*schema.Set)
+func expandZPAApplicationSegmentGroupsFromSet(setInterface interface{}) []common.ZPAApplicationSegmentGroups {
+	set, ok := setInterface.(*schema.Set)
+	if !ok {
+		return []common.ZPAApplicationSegmentGroups{}
+	}
 	var result []common.ZPAApplicationSegmentGroups
 	for _, item := range set.List() {
 		itemMap, _ := item.(map[string]interface{})
@@ -1024,7 +1612,12 @@ func expandWorkloadGroupsIDName(d *schema.ResourceData, key string) []common.Com
 
 // expandIDNameSet takes a Terraform set as input and returns a pointer to a common.IDName struct.
 func expandIDNameSet(d *schema.ResourceData, key string) *common.CommonIDName {
-	idNameList, ok := d.Get(key).(*schema.Set)
+	return expandIDNameFromSet(d.Get(key))
+}
+
+// expandIDNameFromSet is the value-based counterpart of expandIDNameSet.
+func expandIDNameFromSet(v interface{}) *common.CommonIDName {
+	idNameList, ok := v.(*schema.Set)
 	if !ok || idNameList.Len() == 0 {
 		return nil
 	}
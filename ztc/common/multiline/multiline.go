@@ -0,0 +1,14 @@
+package multiline
+
+import "strings"
+
+// TrimLines trims the whitespace around s and around each of its lines. It is
+// the part of description normalization shared by the provider's diff
+// suppression and the normalize_description function.
+func TrimLines(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	return strings.Join(lines, "\n")
+}
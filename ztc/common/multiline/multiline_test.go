@@ -0,0 +1,18 @@
+package multiline
+
+import "testing"
+
+func TestTrimLines(t *testing.T) {
+	cases := map[string]string{
+		"":                        "",
+		"  single line  ":         "single line",
+		"\n  first\n    second\n": "first\nsecond",
+		"first\r\nsecond\r\n":     "first\nsecond",
+		"Cost ${var.x}\n  ok  ":   "Cost ${var.x}\nok",
+	}
+	for in, want := range cases {
+		if got := TrimLines(in); got != want {
+			t.Errorf("TrimLines(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
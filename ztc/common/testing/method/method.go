@@ -2,13 +2,63 @@ package method
 
 import (
 	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 )
 
+var (
+	vcrNamesMu sync.Mutex
+	vcrNames   = map[string]int{}
+)
+
 func GenerateRandomSourcesTypeAndName(sourceType string) (string, string, string) {
-	name := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+	name := randomName()
 	resource := fmt.Sprintf("%s.%s", sourceType, name)
 	dataSource := fmt.Sprintf("data.%s.%s", sourceType, name)
 	return resource, dataSource, name
 }
+
+// randomName returns a random 10 letter name. When acceptance tests record or replay API
+// traffic (ZTC_VCR_TF_ACC or ZTW_VCR_TF_ACC set), names must be the same on every run, so
+// they are derived from the calling test and the number of names it generated so far.
+func randomName() string {
+	if os.Getenv("ZTC_VCR_TF_ACC") == "" && os.Getenv("ZTW_VCR_TF_ACC") == "" {
+		return acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+	}
+
+	test := callingTestName()
+	vcrNamesMu.Lock()
+	vcrNames[test]++
+	seq := vcrNames[test]
+	vcrNamesMu.Unlock()
+
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%s#%d", test, seq)
+	r := rand.New(rand.NewSource(int64(h.Sum64())))
+	b := make([]byte, 10)
+	for i := range b {
+		b[i] = acctest.CharSetAlpha[r.Intn(len(acctest.CharSetAlpha))]
+	}
+	return string(b)
+}
+
+// callingTestName returns the name of the closest Test function on the call stack.
+func callingTestName() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if i := strings.LastIndex(frame.Function, ".Test"); i >= 0 {
+			return frame.Function[i+1:]
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package ztc
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRuleOrderDrifted(t *testing.T) {
+	cases := []struct {
+		name                          string
+		intendedOrder, intendedRank   int
+		effectiveOrder, effectiveRank int
+		want                          bool
+	}{
+		{"same order and rank", 3, 7, 3, 7, false},
+		{"no rank requested", 3, 0, 3, 7, false},
+		{"order zero falls back to one", 0, 7, 1, 7, true},
+		{"order above rule count", 50, 7, 12, 7, true},
+		{"rank changed by API", 3, 1, 3, 7, true},
+	}
+	for _, c := range cases {
+		if got := ruleOrderDrifted(c.intendedOrder, c.intendedRank, c.effectiveOrder, c.effectiveRank); got != c.want {
+			t.Errorf("%s: ruleOrderDrifted = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestSetRuleOrderDrift(t *testing.T) {
+	res := resourceTrafficForwardingLogRule()
+
+	t.Run("warns when the API moved the rule", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+			"name":  "rule",
+			"order": 50,
+			"rank":  7,
+		})
+		d.SetId("1234")
+		d.MarkNewResource()
+		recordIntendedRuleOrder(d)
+		setRuleOrderDrift(d, 12, 7)
+
+		if !d.Get("order_drift").(bool) {
+			t.Fatal("expected order_drift to be true")
+		}
+		diags := ruleOrderDriftDiagnostics(d, "ztc_traffic_forwarding_log_rule")
+		if len(diags) != 1 || diags[0].Severity != diag.Warning {
+			t.Fatalf("expected a single warning, got %v", diags)
+		}
+	})
+
+	t.Run("imported rules adopt the effective order", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+			"name": "rule",
+		})
+		d.SetId("1234")
+		setRuleOrderDrift(d, 4, 7)
+
+		if d.Get("intended_order").(int) != 4 || d.Get("intended_rank").(int) != 7 {
+			t.Errorf("expected intended order/rank 4/7, got %d/%d", d.Get("intended_order"), d.Get("intended_rank"))
+		}
+		if d.Get("order_drift").(bool) {
+			t.Error("expected no drift for an imported rule")
+		}
+		if diags := ruleOrderDriftDiagnostics(d, "ztc_traffic_forwarding_log_rule"); len(diags) != 0 {
+			t.Errorf("expected no diagnostics, got %v", diags)
+		}
+	})
+}
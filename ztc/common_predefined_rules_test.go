@@ -0,0 +1,120 @@
+package ztc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/forwarding_rules"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/traffic_dns_rules"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/traffic_log_rules"
+)
+
+func TestValidatePredefinedRules(t *testing.T) {
+	if err := validatePredefinedRules(forwarding_rules.ForwardingRules{Name: "ZPA Forwarding Rule"}); err == nil {
+		t.Error("expected 'ZPA Forwarding Rule' to be predefined")
+	}
+	if err := validatePredefinedRules(forwarding_rules.ForwardingRules{Name: "my rule"}); err != nil {
+		t.Errorf("expected user rule not to be predefined, got %v", err)
+	}
+	if err := validatePredefinedDNSRules(traffic_dns_rules.ECDNSRules{Name: "ZPA Resolver"}); err == nil {
+		t.Error("expected 'ZPA Resolver' to be predefined")
+	}
+	if err := validatePredefinedLogRules(traffic_log_rules.ECTrafficLogRules{Name: "my log rule", DefaultRule: true}); err == nil {
+		t.Error("expected default log rule to be predefined")
+	}
+	if err := validatePredefinedLogRules(traffic_log_rules.ECTrafficLogRules{Name: "my log rule"}); err != nil {
+		t.Errorf("expected user log rule not to be predefined, got %v", err)
+	}
+	// Log rules are only recognized by their flag, not by DNS rule names
+	if err := validatePredefinedLogRules(traffic_log_rules.ECTrafficLogRules{Name: "ZPA Resolver"}); err != nil {
+		t.Errorf("expected log rule without the default flag not to be predefined, got %v", err)
+	}
+}
+
+func TestReservedRuleNameError(t *testing.T) {
+	if err := reservedRuleNameError("forwarding_control_rule", "Client Connector to ZPA"); err == nil {
+		t.Error("expected the name of a predefined forwarding rule to be reserved")
+	}
+	if err := reservedRuleNameError("traffic_forwarding_dns_rule", "my dns rule"); err != nil {
+		t.Errorf("expected user rule name to be accepted, got %v", err)
+	}
+	if err := reservedRuleNameError("traffic_forwarding_log_rule", "ZPA Resolver"); err != nil {
+		t.Errorf("log rule names are not reserved, got %v", err)
+	}
+}
+
+func TestPredefinedRuleDeleteDiagnostics(t *testing.T) {
+	err := validatePredefinedRules(forwarding_rules.ForwardingRules{Name: "ZPA Forwarding Rule"})
+	diags := predefinedRuleDeleteDiagnostics("ztc_traffic_forwarding_rule", 42, err)
+	if diags.HasError() || len(diags) != 1 {
+		t.Fatalf("expected a single warning, got %v", diags)
+	}
+}
+
+func TestPredefinedRuleDeleteCheck(t *testing.T) {
+	readErr := errors.New("connection reset")
+	stateData := func(predefined bool) *schema.ResourceData {
+		d := resourceTrafficForwardingDNSRule().TestResourceData()
+		_ = d.Set("predefined", predefined)
+		return d
+	}
+	live := func() error {
+		return validatePredefinedDNSRules(traffic_dns_rules.ECDNSRules{Name: "ZPA Resolver"})
+	}
+
+	// The live rule decides when it can be read, whatever the state says
+	if err := predefinedRuleDeleteCheck(stateData(false), nil, live); err == nil {
+		t.Error("expected the live predefined rule to be kept")
+	}
+	// When the read fails, the predefined flag in the state decides
+	if err := predefinedRuleDeleteCheck(stateData(true), readErr, live); err == nil {
+		t.Error("expected the rule marked as predefined in the state to be kept")
+	}
+	if err := predefinedRuleDeleteCheck(stateData(false), readErr, live); err != nil {
+		t.Errorf("expected the user rule to be deleted, got %v", err)
+	}
+}
+
+func TestIsRuleNotFound(t *testing.T) {
+	if !isRuleNotFound(fmt.Errorf("rule with ID 1 %w", errRuleNotFound)) {
+		t.Error("expected a missing rule in the rule list to be not found")
+	}
+	if isRuleNotFound(errors.New("connection reset")) || isRuleNotFound(nil) {
+		t.Error("expected other errors not to be not found")
+	}
+}
+
+func TestRuleOrderSlots(t *testing.T) {
+	// Predefined rules at API orders 1 and 4, and one at order -1 outside of the numbering
+	slots := newRuleOrderSlots([]ruleSlot{
+		{ID: 1, Order: 1, Predefined: true},
+		{ID: 2, Order: 4, Predefined: true},
+		{ID: 3, Order: -1, Predefined: true},
+		{ID: 10, Order: 2},
+		{ID: 11, Order: 3},
+		{ID: 12, Order: 5},
+	})
+	if slots.userRules != 3 {
+		t.Errorf("expected 3 user rules, got %d", slots.userRules)
+	}
+	for userOrder, apiOrder := range map[int]int{1: 2, 2: 3, 3: 5, 4: 6} {
+		if got := slots.apiOrder(userOrder); got != apiOrder {
+			t.Errorf("apiOrder(%d) = %d, want %d", userOrder, got, apiOrder)
+		}
+		if got := slots.userOrder(apiOrder); got != userOrder {
+			t.Errorf("userOrder(%d) = %d, want %d", apiOrder, got, userOrder)
+		}
+	}
+
+	// The predefined rule at order 4 sits between the first and the third user rule
+	if !slots.interleaved(3) || slots.interleaved(2) {
+		t.Errorf("expected the predefined rule at order 4 to be interleaved with 3 user rules only")
+	}
+
+	// Without predefined rules both orders are the same
+	if got := newRuleOrderSlots(nil).apiOrder(7); got != 7 {
+		t.Errorf("apiOrder(7) = %d, want 7", got)
+	}
+}
@@ -1,21 +1,15 @@
 package ztc
 
 import (
+	"context"
+	"errors"
 	"sort"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 )
 
-// resetReorderState clears the global reorder state between tests.
-func resetReorderState() {
-	rules.Lock()
-	defer rules.Unlock()
-	rules.orders = make(map[string]map[int]orderWithState)
-	rules.orderer = nil
-	rules.reorderDone = nil
-}
-
 // =====================================================
 // Sort Logic Tests
 // =====================================================
@@ -104,7 +98,7 @@ func TestRuleIDOrderPairList_SameOrder_TiebreakByID(t *testing.T) {
 // =====================================================
 
 func TestMarkOrderRuleAsDone(t *testing.T) {
-	resetReorderState()
+	rules := newListRules()
 
 	rules.Lock()
 	rules.orders["test_type"] = map[int]orderWithState{
@@ -112,7 +106,7 @@ func TestMarkOrderRuleAsDone(t *testing.T) {
 	}
 	rules.Unlock()
 
-	markOrderRuleAsDone(100, "test_type")
+	rules.markOrderRuleAsDone(100, "test_type")
 
 	rules.Lock()
 	defer rules.Unlock()
@@ -122,20 +116,21 @@ func TestMarkOrderRuleAsDone(t *testing.T) {
 }
 
 func TestReorderWithBeforeReorder_FirstRule_RegistersWithDoneFalse(t *testing.T) {
-	resetReorderState()
+	rules := newListRules()
 	reorderTickInterval = 100 * time.Millisecond
 	defer func() { reorderTickInterval = 30 * time.Second }()
 
-	reorderWithBeforeReorder(
+	rules.reorderWithBeforeReorder(
+		context.Background(),
 		OrderRule{Order: 1, Rank: 7}, 100, "test_reg",
-		func() (int, error) { return 5, nil },
-		func(id int, order OrderRule) error { return nil },
+		func(context.Context) (int, error) { return 5, nil },
+		func(_ context.Context, id int, order OrderRule) error { return nil },
 		nil,
 	)
 
 	rules.Lock()
 	state, exists := rules.orders["test_reg"][100]
-	ch := rules.reorderDone["test_reg"]
+	cycle := rules.cycles["test_reg"]
 	rules.Unlock()
 
 	if !exists {
@@ -144,13 +139,15 @@ func TestReorderWithBeforeReorder_FirstRule_RegistersWithDoneFalse(t *testing.T)
 	if state.done {
 		t.Error("expected rule 100 to be registered with done=false")
 	}
-	if ch == nil {
-		t.Error("expected reorderDone channel to be created")
+	if cycle == nil {
+		t.Error("expected a reorder cycle to be created")
 	}
 
 	// Cleanup: mark done so goroutine can finish
-	markOrderRuleAsDone(100, "test_reg")
-	waitForReorder("test_reg")
+	rules.markOrderRuleAsDone(100, "test_reg")
+	if err := rules.waitForReorder(context.Background(), "test_reg", 100); err != nil {
+		t.Fatal(err)
+	}
 }
 
 // =====================================================
@@ -158,15 +155,15 @@ func TestReorderWithBeforeReorder_FirstRule_RegistersWithDoneFalse(t *testing.T)
 // =====================================================
 
 func TestReorder_AllRulesRegisteredBeforeTick(t *testing.T) {
-	resetReorderState()
+	rules := newListRules()
 	reorderTickInterval = 100 * time.Millisecond
 	defer func() { reorderTickInterval = 30 * time.Second }()
 
 	var mu sync.Mutex
 	reorderedIDs := map[int]int{}
 
-	getCount := func() (int, error) { return 10, nil }
-	updateOrder := func(id int, order OrderRule) error {
+	getCount := func(context.Context) (int, error) { return 10, nil }
+	updateOrder := func(_ context.Context, id int, order OrderRule) error {
 		mu.Lock()
 		reorderedIDs[id] = order.Order
 		mu.Unlock()
@@ -175,7 +172,8 @@ func TestReorder_AllRulesRegisteredBeforeTick(t *testing.T) {
 
 	// Register 5 rules rapidly (all before first tick)
 	for i := 1; i <= 5; i++ {
-		reorderWithBeforeReorder(
+		rules.reorderWithBeforeReorder(
+			context.Background(),
 			OrderRule{Order: i, Rank: 7}, 100+i, "test_all_before",
 			getCount, updateOrder, nil,
 		)
@@ -183,10 +181,12 @@ func TestReorder_AllRulesRegisteredBeforeTick(t *testing.T) {
 
 	// Mark all done
 	for i := 1; i <= 5; i++ {
-		markOrderRuleAsDone(100+i, "test_all_before")
+		rules.markOrderRuleAsDone(100+i, "test_all_before")
 	}
 
-	waitForReorder("test_all_before")
+	if err := rules.waitForReorder(context.Background(), "test_all_before", 101); err != nil {
+		t.Fatal(err)
+	}
 
 	mu.Lock()
 	defer mu.Unlock()
@@ -202,15 +202,15 @@ func TestReorder_AllRulesRegisteredBeforeTick(t *testing.T) {
 }
 
 func TestReorder_LateArrivingRules_NewCycleStarted(t *testing.T) {
-	resetReorderState()
+	rules := newListRules()
 	reorderTickInterval = 100 * time.Millisecond
 	defer func() { reorderTickInterval = 30 * time.Second }()
 
 	var mu sync.Mutex
 	reorderedIDs := map[int]int{}
 
-	getCount := func() (int, error) { return 10, nil }
-	updateOrder := func(id int, order OrderRule) error {
+	getCount := func(context.Context) (int, error) { return 10, nil }
+	updateOrder := func(_ context.Context, id int, order OrderRule) error {
 		mu.Lock()
 		reorderedIDs[id] = order.Order
 		mu.Unlock()
@@ -218,13 +218,15 @@ func TestReorder_LateArrivingRules_NewCycleStarted(t *testing.T) {
 	}
 
 	// Register 2 rules and mark done
-	reorderWithBeforeReorder(OrderRule{Order: 1, Rank: 7}, 101, "test_late", getCount, updateOrder, nil)
-	reorderWithBeforeReorder(OrderRule{Order: 2, Rank: 7}, 102, "test_late", getCount, updateOrder, nil)
-	markOrderRuleAsDone(101, "test_late")
-	markOrderRuleAsDone(102, "test_late")
+	rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 1, Rank: 7}, 101, "test_late", getCount, updateOrder, nil)
+	rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 2, Rank: 7}, 102, "test_late", getCount, updateOrder, nil)
+	rules.markOrderRuleAsDone(101, "test_late")
+	rules.markOrderRuleAsDone(102, "test_late")
 
 	// Wait for first reorder cycle
-	waitForReorder("test_late")
+	if err := rules.waitForReorder(context.Background(), "test_late", 101); err != nil {
+		t.Fatal(err)
+	}
 
 	mu.Lock()
 	firstCycleCount := len(reorderedIDs)
@@ -234,15 +236,17 @@ func TestReorder_LateArrivingRules_NewCycleStarted(t *testing.T) {
 	}
 
 	// Register 3 more rules (late arrivals — after first cycle completed)
-	reorderWithBeforeReorder(OrderRule{Order: 3, Rank: 7}, 103, "test_late", getCount, updateOrder, nil)
-	reorderWithBeforeReorder(OrderRule{Order: 4, Rank: 7}, 104, "test_late", getCount, updateOrder, nil)
-	reorderWithBeforeReorder(OrderRule{Order: 5, Rank: 7}, 105, "test_late", getCount, updateOrder, nil)
-	markOrderRuleAsDone(103, "test_late")
-	markOrderRuleAsDone(104, "test_late")
-	markOrderRuleAsDone(105, "test_late")
+	rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 3, Rank: 7}, 103, "test_late", getCount, updateOrder, nil)
+	rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 4, Rank: 7}, 104, "test_late", getCount, updateOrder, nil)
+	rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 5, Rank: 7}, 105, "test_late", getCount, updateOrder, nil)
+	rules.markOrderRuleAsDone(103, "test_late")
+	rules.markOrderRuleAsDone(104, "test_late")
+	rules.markOrderRuleAsDone(105, "test_late")
 
 	// Wait for second reorder cycle
-	waitForReorder("test_late")
+	if err := rules.waitForReorder(context.Background(), "test_late", 103); err != nil {
+		t.Fatal(err)
+	}
 
 	mu.Lock()
 	defer mu.Unlock()
@@ -259,7 +263,7 @@ func TestReorder_LateArrivingRules_NewCycleStarted(t *testing.T) {
 }
 
 func TestReorder_MultipleResourceTypes_Independent(t *testing.T) {
-	resetReorderState()
+	rules := newListRules()
 	reorderTickInterval = 100 * time.Millisecond
 	defer func() { reorderTickInterval = 30 * time.Second }()
 
@@ -267,14 +271,14 @@ func TestReorder_MultipleResourceTypes_Independent(t *testing.T) {
 	forwardingOrders := map[int]int{}
 	dnsOrders := map[int]int{}
 
-	getCount := func() (int, error) { return 10, nil }
-	forwardingUpdate := func(id int, order OrderRule) error {
+	getCount := func(context.Context) (int, error) { return 10, nil }
+	forwardingUpdate := func(_ context.Context, id int, order OrderRule) error {
 		mu.Lock()
 		forwardingOrders[id] = order.Order
 		mu.Unlock()
 		return nil
 	}
-	dnsUpdate := func(id int, order OrderRule) error {
+	dnsUpdate := func(_ context.Context, id int, order OrderRule) error {
 		mu.Lock()
 		dnsOrders[id] = order.Order
 		mu.Unlock()
@@ -282,24 +286,24 @@ func TestReorder_MultipleResourceTypes_Independent(t *testing.T) {
 	}
 
 	// Register traffic forwarding rules
-	reorderWithBeforeReorder(OrderRule{Order: 1, Rank: 7}, 201, "forwarding_control_rule", getCount, forwardingUpdate, nil)
-	reorderWithBeforeReorder(OrderRule{Order: 2, Rank: 7}, 202, "forwarding_control_rule", getCount, forwardingUpdate, nil)
+	rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 1, Rank: 7}, 201, "forwarding_control_rule", getCount, forwardingUpdate, nil)
+	rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 2, Rank: 7}, 202, "forwarding_control_rule", getCount, forwardingUpdate, nil)
 
 	// Register traffic forwarding DNS rules
-	reorderWithBeforeReorder(OrderRule{Order: 1, Rank: 7}, 301, "traffic_forwarding_dns_rule", getCount, dnsUpdate, nil)
-	reorderWithBeforeReorder(OrderRule{Order: 2, Rank: 7}, 302, "traffic_forwarding_dns_rule", getCount, dnsUpdate, nil)
+	rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 1, Rank: 7}, 301, "traffic_forwarding_dns_rule", getCount, dnsUpdate, nil)
+	rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 2, Rank: 7}, 302, "traffic_forwarding_dns_rule", getCount, dnsUpdate, nil)
 
 	// Mark all done
-	markOrderRuleAsDone(201, "forwarding_control_rule")
-	markOrderRuleAsDone(202, "forwarding_control_rule")
-	markOrderRuleAsDone(301, "traffic_forwarding_dns_rule")
-	markOrderRuleAsDone(302, "traffic_forwarding_dns_rule")
+	rules.markOrderRuleAsDone(201, "forwarding_control_rule")
+	rules.markOrderRuleAsDone(202, "forwarding_control_rule")
+	rules.markOrderRuleAsDone(301, "traffic_forwarding_dns_rule")
+	rules.markOrderRuleAsDone(302, "traffic_forwarding_dns_rule")
 
 	// Wait for both
 	var wg sync.WaitGroup
 	wg.Add(2)
-	go func() { waitForReorder("forwarding_control_rule"); wg.Done() }()
-	go func() { waitForReorder("traffic_forwarding_dns_rule"); wg.Done() }()
+	go func() { _ = rules.waitForReorder(context.Background(), "forwarding_control_rule", 201); wg.Done() }()
+	go func() { _ = rules.waitForReorder(context.Background(), "traffic_forwarding_dns_rule", 301); wg.Done() }()
 	wg.Wait()
 
 	mu.Lock()
@@ -326,15 +330,15 @@ func TestReorder_MultipleResourceTypes_Independent(t *testing.T) {
 }
 
 func TestReorder_ConcurrentRegistration(t *testing.T) {
-	resetReorderState()
+	rules := newListRules()
 	reorderTickInterval = 100 * time.Millisecond
 	defer func() { reorderTickInterval = 30 * time.Second }()
 
 	var mu sync.Mutex
 	reorderedIDs := map[int]int{}
 
-	getCount := func() (int, error) { return 10, nil }
-	updateOrder := func(id int, order OrderRule) error {
+	getCount := func(context.Context) (int, error) { return 10, nil }
+	updateOrder := func(_ context.Context, id int, order OrderRule) error {
 		mu.Lock()
 		reorderedIDs[id] = order.Order
 		mu.Unlock()
@@ -347,13 +351,16 @@ func TestReorder_ConcurrentRegistration(t *testing.T) {
 		wg.Add(1)
 		go func(idx int) {
 			defer wg.Done()
-			reorderWithBeforeReorder(
+			rules.reorderWithBeforeReorder(
+				context.Background(),
 				OrderRule{Order: idx, Rank: 7}, 400+idx, "test_concurrent",
 				getCount, updateOrder, nil,
 			)
 			time.Sleep(50 * time.Millisecond) // simulate API call
-			markOrderRuleAsDone(400+idx, "test_concurrent")
-			waitForReorder("test_concurrent")
+			rules.markOrderRuleAsDone(400+idx, "test_concurrent")
+			if err := rules.waitForReorder(context.Background(), "test_concurrent", 400+idx); err != nil {
+				t.Error(err)
+			}
 		}(i)
 	}
 
@@ -371,3 +378,237 @@ func TestReorder_ConcurrentRegistration(t *testing.T) {
 		}
 	}
 }
+
+func TestReorder_TenantsIndependent(t *testing.T) {
+	reorderTickInterval = 100 * time.Millisecond
+	defer func() { reorderTickInterval = 30 * time.Second }()
+
+	tenantA := &Client{tenantName: "a", rules: newListRules()}
+	tenantB := &Client{tenantName: "b", rules: newListRules()}
+
+	var mu sync.Mutex
+	updates := map[string]map[int]int{"a": {}, "b": {}}
+	updateFor := func(tenant string) func(_ context.Context, id int, order OrderRule) error {
+		return func(_ context.Context, id int, order OrderRule) error {
+			mu.Lock()
+			updates[tenant][id] = order.Order
+			mu.Unlock()
+			return nil
+		}
+	}
+	getCount := func(context.Context) (int, error) { return 10, nil }
+
+	// Both tenants use the same rule type and rule IDs, as two aliased providers would
+	tenantA.rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 1, Rank: 7}, 501, "forwarding_control_rule", getCount, updateFor("a"), nil)
+	tenantA.rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 2, Rank: 7}, 502, "forwarding_control_rule", getCount, updateFor("a"), nil)
+	tenantB.rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 2, Rank: 7}, 501, "forwarding_control_rule", getCount, updateFor("b"), nil)
+
+	tenantA.rules.markOrderRuleAsDone(501, "forwarding_control_rule")
+	tenantA.rules.markOrderRuleAsDone(502, "forwarding_control_rule")
+	// Tenant B finishes without waiting for tenant A, whose rules it never sees
+	tenantB.rules.markOrderRuleAsDone(501, "forwarding_control_rule")
+	if err := tenantB.rules.waitForReorder(context.Background(), "forwarding_control_rule", 501); err != nil {
+		t.Fatal(err)
+	}
+	if err := tenantA.rules.waitForReorder(context.Background(), "forwarding_control_rule", 501); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(updates["a"]) != 2 || updates["a"][501] != 1 || updates["a"][502] != 2 {
+		t.Errorf("tenant a: unexpected reorder %v", updates["a"])
+	}
+	if len(updates["b"]) != 1 || updates["b"][501] != 2 {
+		t.Errorf("tenant b: unexpected reorder %v", updates["b"])
+	}
+}
+
+func TestStartingOrder_PerTenant(t *testing.T) {
+	tenantA := newListRules()
+	tenantB := newListRules()
+
+	calls := 0
+	highest := func(order int) func() int {
+		return func() int {
+			calls++
+			return order
+		}
+	}
+	if got := tenantA.startingOrder("forwarding_control_rule", highest(12)); got != 12 {
+		t.Errorf("tenant a starting order = %d, want 12", got)
+	}
+	if got := tenantA.startingOrder("forwarding_control_rule", highest(40)); got != 12 {
+		t.Errorf("tenant a starting order must be computed once, got %d", got)
+	}
+	if got := tenantB.startingOrder("forwarding_control_rule", highest(0)); got != 1 {
+		t.Errorf("tenant b starting order = %d, want 1", got)
+	}
+	if got := tenantA.startingOrder("traffic_forwarding_dns_rule", highest(3)); got != 3 {
+		t.Errorf("dns starting order = %d, want 3", got)
+	}
+	if calls != 3 {
+		t.Errorf("highest order loaded %d times, want 3", calls)
+	}
+}
+
+func TestReorder_UpdateErrorReportedToRule(t *testing.T) {
+	rules := newListRules()
+	reorderTickInterval = 50 * time.Millisecond
+	defer func() { reorderTickInterval = 30 * time.Second }()
+
+	getCount := func(context.Context) (int, error) { return 10, nil }
+	updateOrder := func(_ context.Context, id int, order OrderRule) error {
+		if id == 602 {
+			return errors.New("STALE_CONFIGURATION_ERROR")
+		}
+		return nil
+	}
+	rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 1, Rank: 7}, 601, "test_errors", getCount, updateOrder, nil)
+	rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 2, Rank: 7}, 602, "test_errors", getCount, updateOrder, nil)
+	rules.markOrderRuleAsDone(601, "test_errors")
+	rules.markOrderRuleAsDone(602, "test_errors")
+
+	if err := rules.waitForReorder(context.Background(), "test_errors", 601); err != nil {
+		t.Errorf("rule 601 was reordered, got %v", err)
+	}
+	err := rules.waitForReorder(context.Background(), "test_errors", 602)
+	if err == nil || !strings.Contains(err.Error(), "STALE_CONFIGURATION_ERROR") {
+		t.Errorf("expected the update error of rule 602, got %v", err)
+	}
+}
+
+func TestReorder_CancelledContextStopsCycle(t *testing.T) {
+	rules := newListRules()
+	reorderTickInterval = 50 * time.Millisecond
+	defer func() { reorderTickInterval = 30 * time.Second }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updated := make(chan int, 2)
+	getCount := func(context.Context) (int, error) { return 10, nil }
+	updateOrder := func(_ context.Context, id int, order OrderRule) error {
+		updated <- id
+		return nil
+	}
+	rules.reorderWithBeforeReorder(ctx, OrderRule{Order: 1, Rank: 7}, 701, "test_cancel", getCount, updateOrder, nil)
+	// A second rule registers but never finishes, so the cycle keeps waiting
+	rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: 2, Rank: 7}, 702, "test_cancel", getCount, updateOrder, nil)
+	rules.markOrderRuleAsDone(701, "test_cancel")
+	cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- rules.waitForReorder(context.Background(), "test_cancel", 702) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected the cancellation to be reported, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("reorder goroutine kept running after its context was cancelled")
+	}
+	if len(updated) != 0 {
+		t.Errorf("no rule should be reordered after cancellation, got %d updates", len(updated))
+	}
+}
+
+func TestWaitForReorder_CallerContext(t *testing.T) {
+	rules := newListRules()
+	reorderTickInterval = 50 * time.Millisecond
+	defer func() { reorderTickInterval = 30 * time.Second }()
+
+	getCount := func(context.Context) (int, error) { return 10, nil }
+	updateOrder := func(context.Context, int, OrderRule) error { return nil }
+	ctx, cancel := context.WithCancel(context.Background())
+	rules.reorderWithBeforeReorder(ctx, OrderRule{Order: 1, Rank: 7}, 801, "test_wait", getCount, updateOrder, nil)
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer waitCancel()
+	// Rule 801 is never marked done, so only the caller's deadline ends the wait
+	if err := rules.waitForReorder(waitCtx, "test_wait", 801); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the caller's deadline to stop the wait, got %v", err)
+	}
+	cancel()
+}
+
+func TestReorder_UserOrdersSkipPredefinedSlots(t *testing.T) {
+	rules := newListRules()
+	reorderTickInterval = 50 * time.Millisecond
+	defer func() { reorderTickInterval = 30 * time.Second }()
+
+	// Two predefined rules hold API orders 1 and 2, followed by three user rules
+	live := []ruleSlot{
+		{ID: 1, Order: 1, Predefined: true},
+		{ID: 2, Order: 2, Predefined: true},
+		{ID: 801, Order: 3},
+		{ID: 802, Order: 4},
+		{ID: 803, Order: 5},
+	}
+	var mu sync.Mutex
+	moved := map[int]int{}
+	lists := 0
+	getCount, updateOrder := userOrderReorder(rules, "test_predefined",
+		func(context.Context) ([]ruleSlot, error) {
+			mu.Lock()
+			lists++
+			mu.Unlock()
+			return live, nil
+		},
+		func(_ context.Context, id int, order OrderRule) error {
+			mu.Lock()
+			moved[id] = order.Order
+			mu.Unlock()
+			return nil
+		},
+	)
+	for i, id := range []int{803, 802, 801} {
+		rules.reorderWithBeforeReorder(context.Background(), OrderRule{Order: i + 1, Rank: 7}, id, "test_predefined", getCount, updateOrder, nil)
+	}
+	for _, id := range []int{801, 802, 803} {
+		rules.markOrderRuleAsDone(id, "test_predefined")
+	}
+	if err := rules.waitForReorder(context.Background(), "test_predefined", 801); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The rule at the highest user order is moved too, past the predefined slots
+	want := map[int]int{803: 3, 802: 4, 801: 5}
+	for id, order := range want {
+		if moved[id] != order {
+			t.Errorf("rule %d: expected API order %d, got %d (moved: %v)", id, order, moved[id], moved)
+		}
+	}
+	// The rules are listed once for the pass, not before every move
+	if lists != 1 {
+		t.Errorf("expected the rules to be listed once, got %d list calls", lists)
+	}
+}
+
+func TestMoveRuleSlot(t *testing.T) {
+	// A predefined rule at API order 3 sits between the user rules
+	slots := []ruleSlot{
+		{ID: 3, Order: -1, Predefined: true},
+		{ID: 1, Order: 1, Predefined: true},
+		{ID: 10, Order: 2},
+		{ID: 2, Order: 3, Predefined: true},
+		{ID: 11, Order: 4},
+	}
+	// Moving rule 11 above rule 10 pushes the predefined rule at order 3 down to order 4
+	got := moveRuleSlot(slots, 11, 2)
+	want := map[int]int{3: -1, 1: 1, 11: 2, 10: 3, 2: 4}
+	for _, s := range got {
+		if want[s.ID] != s.Order {
+			t.Errorf("rule %d: expected order %d, got %d", s.ID, want[s.ID], s.Order)
+		}
+	}
+	if len(got) != len(slots) {
+		t.Errorf("expected %d slots, got %d", len(slots), len(got))
+	}
+	if p := newRuleOrderSlots(got).predefined; len(p) != 2 || p[1] != 4 {
+		t.Errorf("expected predefined orders [1 4], got %v", p)
+	}
+	if slots[4].Order != 4 {
+		t.Error("moveRuleSlot must not change the slots it is given")
+	}
+}
@@ -0,0 +1,46 @@
+package ztc
+
+import "testing"
+
+func TestResolveRelativeRuleOrder(t *testing.T) {
+	list := []ruleAnchor{
+		{ID: 10, Name: "first", Order: 1, Rank: 7},
+		{ID: 20, Name: "second", Order: 2, Rank: 7},
+		{ID: 30, Name: "third", Order: 3, Rank: 7},
+		{ID: 40, Name: "fourth", Order: 4, Rank: 7},
+	}
+	rank := 3
+
+	cases := []struct {
+		name      string
+		anchor    string
+		after     bool
+		selfID    int
+		rank      *int
+		wantOrder int
+		wantRank  int
+	}{
+		{"new rule before anchor by name", "third", false, 0, nil, 3, 7},
+		{"new rule after anchor by ID", "30", true, 0, nil, 4, 7},
+		{"move down below anchor", "third", true, 10, nil, 3, 7},
+		{"move up above anchor", "second", false, 40, nil, 2, 7},
+		{"already before anchor", "third", false, 20, nil, 2, 7},
+		{"configured rank wins", "first", false, 0, &rank, 1, 3},
+	}
+	for _, c := range cases {
+		got, err := resolveRelativeRuleOrder(c.anchor, c.after, c.selfID, c.rank, list)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if got.Order != c.wantOrder || got.Rank != c.wantRank {
+			t.Errorf("%s: got order %d rank %d, want order %d rank %d", c.name, got.Order, got.Rank, c.wantOrder, c.wantRank)
+		}
+	}
+
+	if _, err := resolveRelativeRuleOrder("missing", false, 0, nil, list); err == nil {
+		t.Error("expected an error for an unknown anchor")
+	}
+	if _, err := resolveRelativeRuleOrder("20", true, 20, nil, list); err == nil {
+		t.Error("expected an error when a rule is anchored to itself")
+	}
+}
@@ -4,17 +4,16 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"net/url"
 	"os"
 	"runtime"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/zscaler/terraform-provider-ztc/ztc/common"
+	"github.com/zscaler/terraform-provider-ztc/ztc/tokencache"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw"
 )
@@ -22,24 +21,44 @@ import (
 type (
 	// Config contains our provider schema values and Zscaler clients.
 	Config struct {
-		clientID           string
-		clientSecret       string
-		vanityDomain       string
-		cloud              string
-		privateKey         string
-		httpProxy          string
-		retryCount         int
-		parallelism        int
-		backoff            bool
-		minWait            int
-		maxWait            int
-		logLevel           int
-		requestTimeout     int
-		useLegacyClient    bool
-		zscalerSDKClientV3 *zscaler.Client
-		logger             hclog.Logger
-		TerraformVersion   string // New field for Terraform version
-		ProviderVersion    string // New field for Provider version
+		clientID              string
+		clientSecret          string
+		vanityDomain          string
+		cloud                 string
+		privateKey            string
+		clientSecretFile      string
+		privateKeyFile        string
+		credentialProcess     string
+		oidcToken             string
+		oidcTokenFile         string
+		httpProxy             string
+		proxyUsername         string
+		proxyPassword         string
+		noProxy               string
+		caBundle              string
+		caBundleFile          string
+		clientCertificate     string
+		clientKey             string
+		clientCertificateFile string
+		clientKeyFile         string
+		retryCount            int
+		parallelism           int
+		requestsPerSecond     int
+		limiter               *apiLimiter
+		httpTransport         http.RoundTripper
+		backoff               bool
+		minWait               int
+		maxWait               int
+		logLevel              int
+		requestTimeout        int
+		useLegacyClient       bool
+		logger                hclog.Logger
+		auditLogFile          string
+		auditLog              *auditLog
+		auditTenant           string
+		tokenCacheDir         string
+		TerraformVersion      string // New field for Terraform version
+		ProviderVersion       string // New field for Provider version
 
 		// Options for Legacy V2 SDK
 		Username   string
@@ -52,6 +71,36 @@ type (
 
 type Client struct {
 	Service *zscaler.Service
+
+	// tenantName is the name of the tenants block this client was built from, if any.
+	tenantName string
+	// tenants holds the clients of the provider tenants block, by name.
+	tenants map[string]*Client
+	// changes collects the resources changed through this client for ztc_activation_status.
+	changes *changeSet
+	// rules holds the rule reorder state of the tenant this client talks to.
+	rules *listrules
+	// auditLog is the audit log shared by the provider and its tenants, if any.
+	auditLog *auditLog
+	// legacy is set when the client uses the legacy API framework instead of OneAPI.
+	legacy bool
+
+	// newService builds Service on first use, so configuring the provider makes no API
+	// call and configurations that never reach the API need no credentials.
+	newService  func() (*zscaler.Service, error)
+	serviceOnce sync.Once
+	serviceErr  error
+}
+
+// connect builds the SDK service of the client on first use. Later calls return the
+// result of the first one, so each client authenticates once and reuses its token.
+func (c *Client) connect() error {
+	c.serviceOnce.Do(func() {
+		if c.Service == nil && c.newService != nil {
+			c.Service, c.serviceErr = c.newService()
+		}
+	})
+	return c.serviceErr
 }
 
 func NewConfig(d *schema.ResourceData) *Config {
@@ -61,7 +110,6 @@ func NewConfig(d *schema.ResourceData) *Config {
 		minWait:        30,
 		maxWait:        300,
 		retryCount:     30,
-		parallelism:    1,
 		logLevel:       int(hclog.Error),
 		requestTimeout: 0,
 	}
@@ -69,9 +117,14 @@ func NewConfig(d *schema.ResourceData) *Config {
 	if os.Getenv("TF_LOG") != "" {
 		logLevel = hclog.LevelFromString(os.Getenv("TF_LOG"))
 	}
+	// JSON is the format go-plugin expects from provider logs, and the audit log is a
+	// sub-logger with its own level
 	config.logger = hclog.New(&hclog.LoggerOptions{
-		Level:      logLevel,
-		TimeFormat: "2006/01/02 03:04:05",
+		Name:              "ztc",
+		Level:             logLevel,
+		JSONFormat:        true,
+		TimeFormat:        time.RFC3339Nano,
+		IndependentLevels: true,
 	})
 
 	if val, ok := d.GetOk("use_legacy_client"); ok {
@@ -101,6 +154,41 @@ func NewConfig(d *schema.ResourceData) *Config {
 		config.privateKey = os.Getenv("ZSCALER_PRIVATE_KEY")
 	}
 
+	if val, ok := d.GetOk("client_secret_file"); ok {
+		config.clientSecretFile = val.(string)
+	}
+	if config.clientSecretFile == "" && os.Getenv("ZSCALER_CLIENT_SECRET_FILE") != "" {
+		config.clientSecretFile = os.Getenv("ZSCALER_CLIENT_SECRET_FILE")
+	}
+
+	if val, ok := d.GetOk("private_key_file"); ok {
+		config.privateKeyFile = val.(string)
+	}
+	if config.privateKeyFile == "" && os.Getenv("ZSCALER_PRIVATE_KEY_FILE") != "" {
+		config.privateKeyFile = os.Getenv("ZSCALER_PRIVATE_KEY_FILE")
+	}
+
+	if val, ok := d.GetOk("credential_process"); ok {
+		config.credentialProcess = val.(string)
+	}
+	if config.credentialProcess == "" && os.Getenv("ZSCALER_CREDENTIAL_PROCESS") != "" {
+		config.credentialProcess = os.Getenv("ZSCALER_CREDENTIAL_PROCESS")
+	}
+
+	if val, ok := d.GetOk("oidc_token"); ok {
+		config.oidcToken = val.(string)
+	}
+	if config.oidcToken == "" && os.Getenv("ZSCALER_OIDC_TOKEN") != "" {
+		config.oidcToken = os.Getenv("ZSCALER_OIDC_TOKEN")
+	}
+
+	if val, ok := d.GetOk("oidc_token_file"); ok {
+		config.oidcTokenFile = val.(string)
+	}
+	if config.oidcTokenFile == "" && os.Getenv("ZSCALER_OIDC_TOKEN_FILE") != "" {
+		config.oidcTokenFile = os.Getenv("ZSCALER_OIDC_TOKEN_FILE")
+	}
+
 	if val, ok := d.GetOk("vanity_domain"); ok {
 		config.vanityDomain = val.(string)
 	}
@@ -151,6 +239,10 @@ func NewConfig(d *schema.ResourceData) *Config {
 		config.parallelism = val.(int)
 	}
 
+	if val, ok := d.GetOk("max_requests_per_second"); ok {
+		config.requestsPerSecond = val.(int)
+	}
+
 	if val, ok := d.GetOk("backoff"); ok {
 		config.backoff = val.(bool)
 	}
@@ -178,38 +270,86 @@ func NewConfig(d *schema.ResourceData) *Config {
 		config.httpProxy = os.Getenv("ZSCALER_HTTP_PROXY")
 	}
 
+	for _, opt := range []struct {
+		attr, env string
+		dst       *string
+	}{
+		{"proxy_username", "ZSCALER_PROXY_USERNAME", &config.proxyUsername},
+		{"proxy_password", "ZSCALER_PROXY_PASSWORD", &config.proxyPassword},
+		{"no_proxy", "ZSCALER_NO_PROXY", &config.noProxy},
+		{"ca_bundle", "ZSCALER_CA_BUNDLE", &config.caBundle},
+		{"ca_bundle_file", "ZSCALER_CA_BUNDLE_FILE", &config.caBundleFile},
+		{"client_certificate", "ZSCALER_CLIENT_CERTIFICATE", &config.clientCertificate},
+		{"client_certificate_file", "ZSCALER_CLIENT_CERTIFICATE_FILE", &config.clientCertificateFile},
+		{"client_key", "ZSCALER_CLIENT_KEY", &config.clientKey},
+		{"client_key_file", "ZSCALER_CLIENT_KEY_FILE", &config.clientKeyFile},
+		{"audit_log_file", "ZSCALER_AUDIT_LOG_FILE", &config.auditLogFile},
+		{"token_cache_dir", "ZSCALER_TOKEN_CACHE_DIR", &config.tokenCacheDir},
+	} {
+		if val, ok := d.GetOk(opt.attr); ok {
+			*opt.dst = val.(string)
+		}
+		if *opt.dst == "" && os.Getenv(opt.env) != "" {
+			*opt.dst = os.Getenv(opt.env)
+		}
+	}
+
+	config.limiter = newAPILimiter(config.parallelism, config.requestsPerSecond)
+
 	return &config
 }
 
-// loadClients initializes SDK clients based on configuration
-func (c *Config) loadClients() diag.Diagnostics {
-	if c.useLegacyClient {
-		log.Println("[INFO] Initializing ZTC V2 (Legacy) client")
-		v2Client, err := zscalerSDKV2Client(c)
+// httpClient returns the HTTP client handed to the SDK. All calls made through it use
+// the dedicated proxy and TLS transport and are throttled per endpoint family according
+// to parallelism and max_requests_per_second. When audit_log_file is set, every call
+// is also recorded in the audit log. When token_cache_dir is set, OneAPI tokens are
+// shared through the on-disk token cache.
+func (c *Config) httpClient() (*http.Client, error) {
+	if c.limiter == nil {
+		c.limiter = newAPILimiter(c.parallelism, c.requestsPerSecond)
+	}
+	next := c.httpTransport
+	if next == nil {
+		transport, err := c.newHTTPTransport()
 		if err != nil {
-			return diag.Errorf("failed to initialize SDK V2 client: %v", err)
+			return nil, err
 		}
-		c.zscalerSDKClientV3 = v2Client.Client
-		return nil
+		next = transport
 	}
-
-	log.Println("[INFO] Initializing ZTC V3 client")
-	v3Client, err := zscalerSDKV3Client(c)
-	if err != nil {
-		return diag.Errorf("failed to initialize SDK V3 client: %v", err)
+	if c.oidcToken != "" || c.oidcTokenFile != "" {
+		next = &oidcAssertionTransport{token: c.oidcTokenValue, next: next}
 	}
-	c.zscalerSDKClientV3 = v3Client
-
-	return nil
-}
-
-// SelectClient returns the appropriate client based on authentication type or other factors.
-func (c *Config) SelectClient() (*zscaler.Client, *ztw.Client, error) {
-	if !c.useLegacyClient && c.zscalerSDKClientV3 != nil {
-		return c.zscalerSDKClientV3, nil, nil
+	if c.auditLog != nil {
+		logger, err := c.auditLog.open()
+		if err != nil {
+			return nil, err
+		}
+		if c.auditTenant != "" {
+			logger = logger.With("tenant", c.auditTenant)
+		}
+		next = newAuditTransport(logger, next)
 	}
+	// OIDC assertions are short-lived and exchanged per run, so their tokens are not cached
+	if c.tokenCacheDir != "" && c.oidcToken == "" && c.oidcTokenFile == "" {
+		secret := c.clientSecret
+		if secret == "" {
+			secret = c.privateKey
+		}
+		next = &tokencache.Transport{
+			Dir:    c.tokenCacheDir,
+			Key:    tokencache.NewKey(c.clientID, c.vanityDomain, c.cloud),
+			Secret: secret,
+			Next:   next,
+		}
+	}
+	return newThrottledHTTPClient(c.limiter, next), nil
+}
 
-	return nil, nil, fmt.Errorf("no valid client configuration provided")
+// hasCredentials reports whether any top-level OneAPI or legacy credential, or a source
+// that provides them, is configured.
+func (c *Config) hasCredentials() bool {
+	return c.clientID != "" || c.clientSecret != "" || c.privateKey != "" || c.Username != "" || c.APIKey != "" ||
+		c.clientSecretFile != "" || c.privateKeyFile != "" || c.credentialProcess != ""
 }
 
 // generateUserAgent constructs the user agent string with all required details
@@ -242,12 +382,17 @@ func zscalerSDKV2Client(c *Config) (*zscaler.Service, error) {
 
 	customUserAgent := generateUserAgent(c.TerraformVersion)
 
+	httpClient, err := c.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
 	// Start with base configuration setters
 	setters := []ztw.ConfigSetter{
 		ztw.WithCache(true),
 		ztw.WithCacheTtl(10 * time.Minute), // Cache entries for 10 minutes
 		ztw.WithCacheTti(8 * time.Minute),  // Idle timeout of 8 minutes
-		ztw.WithHttpClientPtr(http.DefaultClient),
+		ztw.WithHttpClientPtr(httpClient),
 		ztw.WithRateLimitMaxRetries(int32(c.retryCount)),
 		ztw.WithRequestTimeout(time.Duration(c.requestTimeout) * time.Second),
 		ztw.WithUserAgentExtra(customUserAgent), // Set the custom user agent
@@ -262,33 +407,7 @@ func zscalerSDKV2Client(c *Config) (*zscaler.Service, error) {
 		ztw.WithZtwCloud(c.ZTCBaseURL),
 	)
 
-	// Configure HTTP proxy if provided
-	if c.httpProxy != "" {
-		_url, err := url.Parse(c.httpProxy)
-		if err != nil {
-			return nil, fmt.Errorf("invalid proxy URL: %v", err)
-		}
-		setters = append(setters, ztw.WithProxyHost(_url.Hostname()))
-
-		// Default to port 80 if not provided
-		sPort := _url.Port()
-		if sPort == "" {
-			sPort = "80"
-		}
-		// Parse the port as a 32-bit integer
-		port64, err := strconv.ParseInt(sPort, 10, 32)
-		if err != nil {
-			return nil, fmt.Errorf("invalid proxy port: %v", err)
-		}
-
-		// Optionally, you can also check the port range if needed
-		if port64 < 1 || port64 > 65535 {
-			return nil, fmt.Errorf("invalid port number: must be between 1 and 65535, got: %d", port64)
-		}
-		// Safe cast to int32
-		port32 := int32(port64)
-		setters = append(setters, ztw.WithProxyPort(port32))
-	}
+	// The proxy, including its credentials and no_proxy, is applied by the HTTP transport
 
 	// Initialize ZTC configuration
 	ztwCfg, err := ztw.NewConfiguration(setters...)
@@ -309,43 +428,23 @@ func zscalerSDKV2Client(c *Config) (*zscaler.Service, error) {
 func zscalerSDKV3Client(c *Config) (*zscaler.Client, error) {
 	customUserAgent := generateUserAgent(c.TerraformVersion)
 
+	httpClient, err := c.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
 	// Start with base configuration setters
 	setters := []zscaler.ConfigSetter{
 		zscaler.WithCache(true),
 		zscaler.WithCacheTtl(10 * time.Minute), // Cache entries for 10 minutes
 		zscaler.WithCacheTti(8 * time.Minute),  // Idle timeout of 8 minutes
-		zscaler.WithHttpClientPtr(http.DefaultClient),
+		zscaler.WithHttpClientPtr(httpClient),
 		zscaler.WithRateLimitMaxRetries(int32(c.retryCount)),
 		zscaler.WithRequestTimeout(time.Duration(c.requestTimeout) * time.Second),
 		zscaler.WithUserAgentExtra(customUserAgent),
 	}
 
-	// Configure HTTP proxy if provided
-	if c.httpProxy != "" {
-		_url, err := url.Parse(c.httpProxy)
-		if err != nil {
-			return nil, err
-		}
-		setters = append(setters, zscaler.WithProxyHost(_url.Hostname()))
-
-		sPort := _url.Port()
-		if sPort == "" {
-			sPort = "80"
-		}
-		// Parse the port as a 32-bit integer
-		port64, err := strconv.ParseInt(sPort, 10, 32)
-		if err != nil {
-			return nil, fmt.Errorf("invalid proxy port: %v", err)
-		}
-
-		// Optionally, you can also check the port range if needed
-		if port64 < 1 || port64 > 65535 {
-			return nil, fmt.Errorf("invalid port number: must be between 1 and 65535, got: %d", port64)
-		}
-		// Safe cast to int32
-		port32 := int32(port64)
-		setters = append(setters, zscaler.WithProxyPort(port32))
-	}
+	// The proxy, including its credentials and no_proxy, is applied by the HTTP transport
 
 	// Main switch for OAuth2 authentication
 	switch {
@@ -392,24 +491,38 @@ func zscalerSDKV3Client(c *Config) (*zscaler.Client, error) {
 	return v3Client.Client, nil
 }
 
+// Client returns the client of the configuration. The SDK client is built and
+// authenticated on first use, not here.
 func (c *Config) Client() (*Client, error) {
+	return &Client{
+		newService: c.newService,
+		changes:    &changeSet{},
+		rules:      newListRules(),
+		legacy:     c.useLegacyClient,
+	}, nil
+}
+
+func (c *Config) newService() (*zscaler.Service, error) {
+	// Credential files and credential_process are only read once the API is needed
+	if err := c.resolveCredentialSources(); err != nil {
+		return nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+
 	// Legacy client logic
 	if c.useLegacyClient {
+		log.Println("[INFO] Initializing ZTC V2 (Legacy) client")
 		wrappedV2Client, err := zscalerSDKV2Client(c)
 		if err != nil {
 			return nil, fmt.Errorf("failed to initialize legacy v2 client: %w", err)
 		}
-		return &Client{
-			Service: zscaler.NewService(wrappedV2Client.Client, nil),
-		}, nil
+		return zscaler.NewService(wrappedV2Client.Client, nil), nil
 	}
 
 	// Fallback to V3 client logic
+	log.Println("[INFO] Initializing ZTC V3 client")
 	v3Client, err := zscalerSDKV3Client(c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize v3 client: %w", err)
 	}
-	return &Client{
-		Service: zscaler.NewService(v3Client, nil),
-	}, nil
+	return zscaler.NewService(v3Client, nil), nil
 }
@@ -0,0 +1,208 @@
+package ztc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// credentialProcessTimeout bounds how long the credential_process command may run.
+var credentialProcessTimeout = 60 * time.Second
+
+// credentialProcessOutput is the JSON document a credential_process command prints on
+// stdout. Only the fields that are not already configured are used.
+type credentialProcessOutput struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	PrivateKey   string `json:"private_key"`
+	VanityDomain string `json:"vanity_domain"`
+	Cloud        string `json:"zscaler_cloud"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	APIKey       string `json:"api_key"`
+}
+
+// resolveCredentialSources fills the credentials from client_secret_file,
+// private_key_file and credential_process, and prepares the OIDC federated token when
+// one is configured. credential_process cannot be combined with another source of the
+// client secret or private key, including environment variables, because one of them
+// would be silently ignored. For the other fields, values set directly in the
+// configuration or environment win.
+func (c *Config) resolveCredentialSources() error {
+	if c.credentialProcess != "" {
+		for _, source := range []struct{ name, value string }{
+			{"client_secret", c.clientSecret},
+			{"private_key", c.privateKey},
+			{"client_secret_file", c.clientSecretFile},
+			{"private_key_file", c.privateKeyFile},
+			{"oidc_token", c.oidcToken},
+			{"oidc_token_file", c.oidcTokenFile},
+		} {
+			if source.value != "" {
+				return fmt.Errorf("credential_process cannot be combined with %s; unset it, including its ZSCALER_ environment variable", source.name)
+			}
+		}
+	}
+	if c.clientSecretFile != "" && c.clientSecret == "" {
+		secret, err := readSecretFile(c.clientSecretFile)
+		if err != nil {
+			return fmt.Errorf("client_secret_file: %w", err)
+		}
+		c.clientSecret = secret
+	}
+	if c.privateKeyFile != "" && c.privateKey == "" {
+		key, err := readSecretFile(c.privateKeyFile)
+		if err != nil {
+			return fmt.Errorf("private_key_file: %w", err)
+		}
+		c.privateKey = key
+	}
+
+	if c.credentialProcess != "" {
+		out, err := runCredentialProcess(c.credentialProcess)
+		if err != nil {
+			return err
+		}
+		if out.ClientSecret != "" && out.PrivateKey != "" {
+			return fmt.Errorf("credential_process must print either client_secret or private_key, not both")
+		}
+		setIfEmpty(&c.clientID, out.ClientID)
+		c.clientSecret = out.ClientSecret
+		c.privateKey = out.PrivateKey
+		setIfEmpty(&c.vanityDomain, out.VanityDomain)
+		setIfEmpty(&c.cloud, out.Cloud)
+		setIfEmpty(&c.Username, out.Username)
+		setIfEmpty(&c.Password, out.Password)
+		setIfEmpty(&c.APIKey, out.APIKey)
+	}
+
+	if c.oidcToken != "" || c.oidcTokenFile != "" {
+		if c.clientSecret != "" || c.privateKey != "" {
+			return fmt.Errorf("oidc_token and oidc_token_file cannot be combined with client_secret or private_key")
+		}
+		// The SDK requires a client secret to select the client credentials flow; the
+		// OIDC transport replaces it with the federated token on every token request.
+		c.clientSecret = oidcClientSecretPlaceholder
+	}
+	return nil
+}
+
+func setIfEmpty(dst *string, value string) {
+	if *dst == "" {
+		*dst = value
+	}
+}
+
+// readSecretFile reads a credential from a file that must only be accessible by its owner.
+func readSecretFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("%s has permissions %#o; it must not be accessible by group or others (chmod 600)", path, info.Mode().Perm())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// runCredentialProcess runs the configured command through the shell and decodes the
+// credentials it prints as JSON.
+func runCredentialProcess(command string) (*credentialProcessOutput, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialProcessTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("credential_process failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	out := &credentialProcessOutput{}
+	if err := json.Unmarshal(stdout.Bytes(), out); err != nil {
+		return nil, fmt.Errorf("credential_process must print a JSON object: %v", err)
+	}
+	log.Printf("[INFO] Loaded credentials from credential_process")
+	return out, nil
+}
+
+const (
+	oidcClientSecretPlaceholder = "oidc-federated-token"
+	oidcClientAssertionType     = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// oidcTokenValue returns the federated token, re-reading oidc_token_file on every call because
+// workload identity tokens are rotated on disk.
+func (c *Config) oidcTokenValue() (string, error) {
+	if c.oidcTokenFile != "" {
+		data, err := os.ReadFile(c.oidcTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("reading oidc_token_file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return c.oidcToken, nil
+}
+
+// oidcAssertionTransport authenticates OneAPI token requests with an OIDC federated
+// token: the client secret in the request is replaced by a JWT client assertion.
+type oidcAssertionTransport struct {
+	token func() (string, error)
+	next  http.RoundTripper
+}
+
+func (t *oidcAssertionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, "/oauth2/v1/token") || req.Body == nil {
+		return next.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	token, err := t.token()
+	if err != nil {
+		return nil, err
+	}
+	form.Del("client_secret")
+	form.Set("client_assertion_type", oidcClientAssertionType)
+	form.Set("client_assertion", token)
+	encoded := form.Encode()
+
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(strings.NewReader(encoded))
+	out.ContentLength = int64(len(encoded))
+	out.Header.Set("Content-Length", strconv.Itoa(len(encoded)))
+	out.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return next.RoundTrip(out)
+}
@@ -0,0 +1,164 @@
+package ztc
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestReadSecretFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret")
+	if err := os.WriteFile(path, []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	secret, err := readSecretFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret != "s3cr3t" {
+		t.Fatalf("got %q, want %q", secret, "s3cr3t")
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readSecretFile(path); err == nil {
+		t.Fatal("expected an error for a world readable secret file")
+	}
+}
+
+func TestRunCredentialProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	out, err := runCredentialProcess(`echo '{"client_id":"id","client_secret":"secret","vanity_domain":"acme"}'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.ClientID != "id" || out.ClientSecret != "secret" || out.VanityDomain != "acme" {
+		t.Fatalf("unexpected credentials: %+v", out)
+	}
+
+	if _, err := runCredentialProcess("echo not-json"); err == nil {
+		t.Fatal("expected an error for output that is not JSON")
+	}
+	if _, err := runCredentialProcess("exit 3"); err == nil {
+		t.Fatal("expected an error when the command fails")
+	}
+}
+
+func TestOIDCAssertionTransport(t *testing.T) {
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got, _ = url.ParseQuery(string(body))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &oidcAssertionTransport{
+		token: func() (string, error) { return "federated.jwt", nil },
+	}}
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {"id"},
+		"client_secret": {oidcClientSecretPlaceholder},
+	}
+	resp, err := client.Post(server.URL+"/oauth2/v1/token", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got.Get("client_secret") != "" {
+		t.Fatal("client_secret must not be sent when using a federated token")
+	}
+	if got.Get("client_assertion") != "federated.jwt" || got.Get("client_assertion_type") != oidcClientAssertionType {
+		t.Fatalf("unexpected token request: %v", got)
+	}
+	if got.Get("client_id") != "id" {
+		t.Fatalf("client_id must be preserved, got %v", got)
+	}
+}
+
+func TestCredentialProcessConflicts(t *testing.T) {
+	// A secret from the environment must not silently win over credential_process
+	c := &Config{credentialProcess: "exit 1", clientSecret: "from-env"}
+	err := c.resolveCredentialSources()
+	if err == nil || !strings.Contains(err.Error(), "client_secret") {
+		t.Fatalf("expected a conflict with client_secret, got %v", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	c = &Config{credentialProcess: `echo '{"client_secret":"s","private_key":"k"}'`}
+	if err := c.resolveCredentialSources(); err == nil {
+		t.Fatal("expected an error when credential_process prints both a secret and a key")
+	}
+}
+
+// TestOIDCTokenRequest checks the token request that reaches the identity provider when
+// an OIDC token is configured: the SDK sends the client secret placeholder set by
+// resolveCredentialSources, and the transport replaces it with the client assertion.
+func TestOIDCTokenRequest(t *testing.T) {
+	var got url.Values
+	var contentLength int64
+	var bodyLength int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got, _ = url.ParseQuery(string(body))
+		contentLength, bodyLength = r.ContentLength, len(body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"access_token":"token","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	c := &Config{clientID: "id", vanityDomain: "acme", oidcToken: "federated.jwt", httpTransport: http.DefaultTransport}
+	if err := c.resolveCredentialSources(); err != nil {
+		t.Fatal(err)
+	}
+	if c.clientSecret != oidcClientSecretPlaceholder {
+		t.Fatalf("the SDK needs the client secret placeholder to select the client credentials flow, got %q", c.clientSecret)
+	}
+	client, err := c.httpClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The form sent by the SDK for the client credentials flow
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.clientID},
+		"client_secret": {c.clientSecret},
+		"audience":      {"https://api.zscaler.com"},
+	}
+	resp, err := client.Post(server.URL+"/oauth2/v1/token", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	want := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_id":             {"id"},
+		"audience":              {"https://api.zscaler.com"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {"federated.jwt"},
+	}
+	if got.Encode() != want.Encode() {
+		t.Fatalf("unexpected token request:\n got %s\nwant %s", got.Encode(), want.Encode())
+	}
+	if contentLength != int64(bodyLength) {
+		t.Fatalf("Content-Length %d does not match the %d byte body", contentLength, bodyLength)
+	}
+}
@@ -27,14 +27,7 @@ func dataSourceActivationStatus() *schema.Resource {
 				Computed:    true,
 				Description: "Admin activation status",
 			},
-			"admin_status_map": {
-				Type:     schema.TypeMap,
-				Computed: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-				Description: "Admin status",
-			},
+			"admin_status": adminStatusSchema(),
 		},
 	}
 }
@@ -52,7 +45,7 @@ func dataSourceActivationStatusRead(ctx context.Context, d *schema.ResourceData,
 		d.SetId("activation")
 		_ = d.Set("org_edit_status", resp.OrgEditStatus)
 		_ = d.Set("org_last_activate_status", resp.OrgLastActivateStatus)
-		_ = d.Set("admin_status_map", resp.AdminStatusMap)
+		_ = d.Set("admin_status", flattenAdminStatus(resp.AdminStatusMap))
 		_ = d.Set("admin_activate_status", resp.AdminActivateStatus)
 
 	} else {
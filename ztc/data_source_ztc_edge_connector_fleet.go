@@ -0,0 +1,405 @@
+package ztc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/ecgroup"
+)
+
+func dataSourceEdgeConnectorFleet() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceEdgeConnectorFleetRead,
+		Schema: map[string]*schema.Schema{
+			"platform": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only include edge connector groups deployed on this platform (case-insensitive).",
+			},
+			"availability_zone": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only include edge connector groups deployed in this AWS or Azure availability zone.",
+			},
+			"build_version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only include VMs running exactly this build version.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only include edge connector groups reporting this status (case-insensitive).",
+			},
+			"target_build_version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Build version the fleet is expected to run. VMs on an older build are counted in `vms_behind_target_count`.",
+			},
+			"group_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of edge connector groups matching the filters. With build_version set, only groups with a VM on that build are counted.",
+			},
+			"vm_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of edge connector VMs matching the filters.",
+			},
+			"instance_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of edge connector instances running on the matching VMs.",
+			},
+			"vms_behind_target_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of matching VMs running a build older than `target_build_version`. Always 0 when no target is set.",
+			},
+			"build_version_counts": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Number of matching VMs per build version.",
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"upgrade_status_counts": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Number of matching VMs per upgrade status code.",
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"vms": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Flat list of edge connector VMs across all matching groups.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"group_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"group_status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"platform": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"availability_zone": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"form_factor": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"nat_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"zia_gateway": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"zpa_broker": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"build_version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"behind_target": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"last_upgrade_time": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"upgrade_status": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"upgrade_start_time": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"upgrade_end_time": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"instances": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Flat list of edge connector instances across all matching VMs.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"vm_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"vm_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ec_instance_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"out_gw_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"nat_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"dns_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// edgeConnectorFleetFilter holds the optional filters of the ztc_edge_connector_fleet data source.
+type edgeConnectorFleetFilter struct {
+	platform         string
+	availabilityZone string
+	buildVersion     string
+	status           string
+}
+
+func (f edgeConnectorFleetFilter) matchesGroup(group *ecgroup.EcGroup) bool {
+	if f.platform != "" && !strings.EqualFold(group.Platform, f.platform) {
+		return false
+	}
+	if f.availabilityZone != "" && group.AWSAvailabilityZone != f.availabilityZone && group.AzureAvailabilityZone != f.availabilityZone {
+		return false
+	}
+	if f.status != "" {
+		found := false
+		for _, s := range group.Status {
+			if strings.EqualFold(s, f.status) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (f edgeConnectorFleetFilter) matchesBuildVersion(buildVersion string) bool {
+	return f.buildVersion == "" || buildVersion == f.buildVersion
+}
+
+func dataSourceEdgeConnectorFleetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	filter := edgeConnectorFleetFilter{
+		platform:         d.Get("platform").(string),
+		availabilityZone: d.Get("availability_zone").(string),
+		buildVersion:     d.Get("build_version").(string),
+		status:           d.Get("status").(string),
+	}
+	targetBuild := d.Get("target_build_version").(string)
+
+	log.Printf("[INFO] Getting data for all edge connector groups\n")
+	groups, err := ecgroup.GetAll(ctx, service)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+
+	vms := []map[string]interface{}{}
+	instances := []map[string]interface{}{}
+	buildCounts := map[string]interface{}{}
+	upgradeStatusCounts := map[string]interface{}{}
+	groupCount := 0
+	behindTarget := 0
+
+	for i := range groups {
+		group := &groups[i]
+		if !filter.matchesGroup(group) {
+			continue
+		}
+		// With a build_version filter, only groups with a matching VM are counted
+		matchedVM := false
+
+		var groupStatus string
+		if len(group.Status) > 0 {
+			groupStatus = group.Status[0]
+		}
+		az := group.AWSAvailabilityZone
+		if az == "" {
+			az = group.AzureAvailabilityZone
+		}
+
+		for _, vm := range group.ECVMs {
+			if !filter.matchesBuildVersion(vm.BuildVersion) {
+				continue
+			}
+			matchedVM = true
+			behind := targetBuild != "" && compareBuildVersions(vm.BuildVersion, targetBuild) < 0
+			if behind {
+				behindTarget++
+			}
+			buildCounts[vm.BuildVersion] = countFromMap(buildCounts, vm.BuildVersion) + 1
+			statusKey := fmt.Sprintf("%d", vm.UpgradeStatus)
+			upgradeStatusCounts[statusKey] = countFromMap(upgradeStatusCounts, statusKey) + 1
+
+			vms = append(vms, map[string]interface{}{
+				"group_id":           group.ID,
+				"group_name":         group.Name,
+				"group_status":       groupStatus,
+				"platform":           group.Platform,
+				"availability_zone":  az,
+				"id":                 vm.ID,
+				"name":               vm.Name,
+				"form_factor":        vm.FormFactor,
+				"nat_ip":             vm.NATIP,
+				"zia_gateway":        vm.ZiaGateway,
+				"zpa_broker":         vm.ZpaBroker,
+				"build_version":      vm.BuildVersion,
+				"behind_target":      behind,
+				"last_upgrade_time":  vm.LastUpgradeTime,
+				"upgrade_status":     vm.UpgradeStatus,
+				"upgrade_start_time": vm.UpgradeStartTime,
+				"upgrade_end_time":   vm.UpgradeEndTime,
+			})
+
+			for _, instance := range vm.ECInstances {
+				instances = append(instances, map[string]interface{}{
+					"group_id":         group.ID,
+					"vm_id":            vm.ID,
+					"vm_name":          vm.Name,
+					"ec_instance_type": instance.ECInstanceType,
+					"out_gw_ip":        instance.OutGwIp,
+					"nat_ip":           instance.NatIP,
+					"dns_ip":           instance.DNSIP,
+				})
+			}
+		}
+		if matchedVM || filter.buildVersion == "" {
+			groupCount++
+		}
+	}
+
+	d.SetId("edge_connector_fleet")
+	_ = d.Set("group_count", groupCount)
+	_ = d.Set("vm_count", len(vms))
+	_ = d.Set("instance_count", len(instances))
+	_ = d.Set("vms_behind_target_count", behindTarget)
+	if err := d.Set("build_version_counts", buildCounts); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("upgrade_status_counts", upgradeStatusCounts); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("vms", vms); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("instances", instances); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] Retrieved %d edge connector groups, %d VMs and %d instances\n", groupCount, len(vms), len(instances))
+	return nil
+}
+
+func countFromMap(m map[string]interface{}, key string) int {
+	if v, ok := m[key].(int); ok {
+		return v
+	}
+	return 0
+}
+
+// compareBuildVersions compares two dotted build versions (e.g. "23.312.2")
+// numerically, segment by segment. Non-numeric segments are compared as strings, and a
+// version without them sorts after its pre-releases ("23.312.2" > "23.312.2-rc1").
+// It returns -1 when a is older than b, 1 when a is newer and 0 when they are equal.
+func compareBuildVersions(a, b string) int {
+	as := strings.FieldsFunc(a, isBuildVersionSeparator)
+	bs := strings.FieldsFunc(b, isBuildVersionSeparator)
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xi, xErr := strconv.Atoi(x)
+		yi, yErr := strconv.Atoi(y)
+		// A release sorts after its pre-releases: a missing part is greater than a
+		// text part such as "rc1", and equal to a numeric 0
+		if x == "" && yErr != nil {
+			return 1
+		}
+		if y == "" && xErr != nil {
+			return -1
+		}
+		if x == "" {
+			xi, xErr = 0, nil
+		}
+		if y == "" {
+			yi, yErr = 0, nil
+		}
+		if xErr == nil && yErr == nil {
+			if xi != yi {
+				if xi < yi {
+					return -1
+				}
+				return 1
+			}
+			continue
+		}
+		if c := strings.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func isBuildVersionSeparator(r rune) bool {
+	return r == '.' || r == '-' || r == '_'
+}
@@ -0,0 +1,57 @@
+package ztc
+
+import (
+	"testing"
+
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/ecgroup"
+)
+
+func TestCompareBuildVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"23.312.2", "23.312.2", 0},
+		{"23.312.2", "23.312.10", -1},
+		{"24.1.0", "23.312.10", 1},
+		{"23.312", "23.312.0", 0},
+		{"23.312", "23.312.1", -1},
+		{"", "1.0", -1},
+		{"23.312.2-rc1", "23.312.2-rc2", -1},
+		{"23.312.2", "23.312.2-rc1", 1},
+		{"23.312.2-rc1", "23.312.2", -1},
+		{"23.312.2-rc1", "23.312.3", -1},
+	}
+	for _, c := range cases {
+		if got := compareBuildVersions(c.a, c.b); got != c.want {
+			t.Errorf("compareBuildVersions(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestEdgeConnectorFleetFilter_MatchesGroup(t *testing.T) {
+	group := &ecgroup.EcGroup{
+		Platform:            "AWS",
+		AWSAvailabilityZone: "us-east-1a",
+		Status:              []string{"ENABLED"},
+	}
+
+	cases := []struct {
+		name   string
+		filter edgeConnectorFleetFilter
+		want   bool
+	}{
+		{"no filters", edgeConnectorFleetFilter{}, true},
+		{"platform case-insensitive", edgeConnectorFleetFilter{platform: "aws"}, true},
+		{"platform mismatch", edgeConnectorFleetFilter{platform: "AZURE"}, false},
+		{"availability zone", edgeConnectorFleetFilter{availabilityZone: "us-east-1a"}, true},
+		{"availability zone mismatch", edgeConnectorFleetFilter{availabilityZone: "us-east-1b"}, false},
+		{"status", edgeConnectorFleetFilter{status: "enabled"}, true},
+		{"status mismatch", edgeConnectorFleetFilter{status: "DISABLED"}, false},
+	}
+	for _, c := range cases {
+		if got := c.filter.matchesGroup(group); got != c.want {
+			t.Errorf("%s: matchesGroup = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
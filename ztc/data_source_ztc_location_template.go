@@ -103,6 +103,26 @@ func dataSourceLocationTemplate() *schema.Resource {
 							Computed:    true,
 							Description: "Download bandwidth in Kbps. The value 0 implies no Bandwidth Control enforcement.",
 						},
+						"idle_time_in_minutes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Idle Time to Disassociation when surrogate IP is enabled",
+						},
+						"surrogate_ip": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Indicates whether surrogate IP is enabled for this location",
+						},
+						"surrogate_ip_enforced_for_known_browsers": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Indicates whether surrogate IP is enforced for known browsers",
+						},
+						"surrogate_refresh_time_in_minutes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Refresh Time for re-validation of Surrogacy",
+						},
 					},
 				},
 			},
@@ -174,6 +194,10 @@ func flattenTemplateDetails(template *locationtemplate.LocationTemplateDetails)
 			"enforce_bandwidth_control": template.EnforceBandwidthControl,
 			"up_bandwidth":              template.UpBandwidth,
 			"dn_bandwidth":              template.DnBandwidth,
+			"idle_time_in_minutes":      template.IdleTimeInMinutes,
+			"surrogate_ip":              template.SurrogateIP,
+			"surrogate_ip_enforced_for_known_browsers": template.SurrogateIPEnforcedForKnownBrowsers,
+			"surrogate_refresh_time_in_minutes":        template.SurrogateRefreshTimeInMinutes,
 		},
 	}
 }
@@ -85,6 +85,212 @@ func dataSourceTrafficForwardingLogRule() *schema.Resource {
 					},
 				},
 			},
+			"location_groups": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Name-ID pairs of the location groups to which the forwarding rule applies",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Identifier that uniquely identifies an entity",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The configured name of the entity",
+						},
+						"extensions": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"src_ips": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "User-defined source IP addresses for which the rule is applicable. If not set, the rule is not restricted to a specific source IP address.",
+			},
+			"src_ip_groups": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Source IP address groups for which the rule is applicable. If not set, the rule is not restricted to a specific source IP address group.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Identifier that uniquely identifies an entity",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The configured name of the entity",
+						},
+						"extensions": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"dest_addresses": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "List of destination IP addresses or FQDNs for which the rule is applicable. CIDR notation can be used for destination IP addresses. If not set, the rule is not restricted to a specific destination addresses unless specified by destCountries, destIpGroups, or destIpCategories.",
+			},
+			"dest_ip_categories": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "List of destination IP categories to which the rule applies. If not set, the rule is not restricted to specific destination IP categories.",
+			},
+			"res_categories": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "List of destination domain categories to which the rule applies",
+			},
+			"dest_countries": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Destination countries for which the rule is applicable. If not set, the rule is not restricted to specific destination countries.",
+			},
+			"dest_ip_groups": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "User-defined destination IP address groups to which the rule is applied. If not set, the rule is not restricted to a specific destination IP address group.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Identifier that uniquely identifies an entity",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The configured name of the entity",
+						},
+						"extensions": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"nw_services": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "User-defined network services to which the rule applies. If not set, the rule is not restricted to a specific network service. Note: When the forwarding method is Proxy Chaining, only TCP-based network services are considered for policy match .",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Identifier that uniquely identifies an entity",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The configured name of the entity",
+						},
+						"extensions": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Description: "General purpose",
+						},
+					},
+				},
+			},
+			"nw_service_groups": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "User-defined network service group to which the rule applies. If not set, the rule is not restricted to a specific network service group.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Identifier that uniquely identifies an entity",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The configured name of the entity",
+						},
+						"extensions": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"src_workload_groups": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The list of preconfigured workload groups to which the policy must be applied",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Identifier that uniquely identifies an entity",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The configured name of the entity",
+						},
+					},
+				},
+			},
+			"app_service_groups": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of application service groups",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Identifier that uniquely identifies an entity",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The configured name of the entity",
+						},
+						"extensions": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
 			"proxy_gateway": {
 				Type:        schema.TypeList,
 				Computed:    true,
@@ -161,15 +367,48 @@ func dataSourceTrafficForwardingLogRuleRead(ctx context.Context, d *schema.Resou
 		_ = d.Set("type", resp.Type)
 		_ = d.Set("default_rule", resp.DefaultRule)
 		_ = d.Set("forward_method", resp.ForwardMethod)
+		_ = d.Set("src_ips", resp.SrcIps)
+		_ = d.Set("dest_addresses", resp.DestAddresses)
+		_ = d.Set("dest_ip_categories", resp.DestIpCategories)
+		_ = d.Set("dest_countries", resp.DestCountries)
+		_ = d.Set("res_categories", resp.ResCategories)
 
 		if err := d.Set("locations", flattenIDNameExtensions(resp.Locations)); err != nil {
 			return diag.FromErr(err)
 		}
 
+		if err := d.Set("location_groups", flattenIDNameExtensions(resp.LocationsGroups)); err != nil {
+			return diag.FromErr(err)
+		}
+
 		if err := d.Set("ec_groups", flattenIDNameExtensions(resp.ECGroups)); err != nil {
 			return diag.FromErr(err)
 		}
 
+		if err := d.Set("src_ip_groups", flattenIDNameExtensions(resp.SrcIpGroups)); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := d.Set("dest_ip_groups", flattenIDNameExtensions(resp.DestIpGroups)); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := d.Set("src_workload_groups", flattenIDNameExtensions(resp.SrcWorkloadGroups)); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := d.Set("nw_services", flattenIDNameExtensions(resp.NwServices)); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := d.Set("nw_service_groups", flattenIDNameExtensions(resp.NwServiceGroups)); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := d.Set("app_service_groups", flattenIDNameExtensions(resp.AppServiceGroups)); err != nil {
+			return diag.FromErr(err)
+		}
+
 		if err := d.Set("proxy_gateway", flattenIDNameSet(resp.ProxyGateway)); err != nil {
 			return diag.FromErr(err)
 		}
@@ -132,18 +132,18 @@ func dataSourceWorkloadGroupRead(ctx context.Context, d *schema.ResourceData, me
 	// Check if searching by ID
 	id, ok := getIntFromResourceData(d, "id")
 	if ok {
-		log.Printf("[INFO] Getting time window by id: %d\n", id)
+		log.Printf("[INFO] Getting workload group by id: %d\n", id)
 		searchCriteria = fmt.Sprintf("id=%d", id)
 
-		// Get all time windows and find the one with matching ID
-		allTimeWindows, err := workload_groups.GetAll(ctx, service)
+		// Get all workload groups and find the one with matching ID
+		allGroups, err := workload_groups.GetAll(ctx, service)
 		if err != nil {
 			return diag.FromErr(err)
 		}
 
-		for _, tw := range allTimeWindows {
-			if tw.ID == id {
-				resp = &tw
+		for i := range allGroups {
+			if allGroups[i].ID == id {
+				resp = &allGroups[i]
 				break
 			}
 		}
@@ -152,7 +152,7 @@ func dataSourceWorkloadGroupRead(ctx context.Context, d *schema.ResourceData, me
 	// Check if searching by name (only if ID search didn't find anything)
 	name, _ := d.Get("name").(string)
 	if resp == nil && name != "" {
-		log.Printf("[INFO] Getting time window by name: %s\n", name)
+		log.Printf("[INFO] Getting workload group by name: %s\n", name)
 		searchCriteria = fmt.Sprintf("name=%s", name)
 
 		res, err := workload_groups.GetByName(ctx, service, name)
@@ -0,0 +1,74 @@
+package ztc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/provisioning/provisioning_url"
+)
+
+// ephemeralProvisioningURL fetches a provisioning URL for the duration of a Terraform
+// run, so the bootstrap secret in prov_url is never written to state or plan files.
+func ephemeralProvisioningURL() ephemeralResource {
+	return ephemeralResource{
+		schema: &tfprotov5.Schema{
+			Block: &tfprotov5.SchemaBlock{
+				Description: "Fetches a provisioning URL for the duration of a Terraform run without storing it in state or plan files.",
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{Name: "id", Type: tftypes.Number, Optional: true, Computed: true, Description: "ID of the provisioning URL"},
+					{Name: "name", Type: tftypes.String, Optional: true, Computed: true, Description: "Name of the provisioning URL"},
+					{Name: "tenant", Type: tftypes.String, Optional: true, Description: "Name of the provider `tenants` block whose credentials are used. Defaults to the provider's default credentials"},
+					{Name: "desc", Type: tftypes.String, Computed: true, Description: "Description of the provisioning URL"},
+					{Name: "prov_url", Type: tftypes.String, Computed: true, Sensitive: true, Description: "Provisioning URL used to bootstrap Cloud Connectors"},
+					{Name: "prov_url_type", Type: tftypes.String, Computed: true, Description: "Type of the provisioning URL"},
+					{Name: "status", Type: tftypes.String, Computed: true, Description: "Deployment status of the provisioning URL"},
+				},
+			},
+		},
+		validate: func(config map[string]tftypes.Value) []*tfprotov5.Diagnostic {
+			if config["id"].IsNull() && config["name"].IsNull() {
+				return ephemeralError("ztc_provisioning_url", fmt.Errorf("one of id or name must be set"))
+			}
+			return nil
+		},
+		open: ephemeralProvisioningURLOpen,
+	}
+}
+
+func ephemeralProvisioningURLOpen(ctx context.Context, config map[string]tftypes.Value, meta interface{}) (map[string]tftypes.Value, []*tfprotov5.Diagnostic) {
+	tenantMeta, err := tenantMetaFor(meta, ephemeralString(config["tenant"]))
+	if err != nil {
+		return nil, ephemeralError("ztc_provisioning_url", err)
+	}
+	zClient := tenantMeta.(*Client)
+	service := zClient.Service
+
+	var resp *provisioning_url.ProvisioningURL
+	if id, ok := ephemeralInt(config["id"]); ok {
+		log.Printf("[INFO] Getting ephemeral provisioning url id: %d\n", id)
+		resp, err = provisioning_url.Get(ctx, service, id)
+	} else {
+		name := ephemeralString(config["name"])
+		log.Printf("[INFO] Getting ephemeral provisioning url name: %s\n", name)
+		resp, err = provisioning_url.GetByName(ctx, service, name)
+	}
+	if err != nil {
+		return nil, ephemeralError("ztc_provisioning_url", err)
+	}
+	if resp == nil {
+		return nil, ephemeralError("ztc_provisioning_url", fmt.Errorf("couldn't find the provisioning url"))
+	}
+
+	return map[string]tftypes.Value{
+		"id":            tftypes.NewValue(tftypes.Number, big.NewFloat(float64(resp.ID))),
+		"name":          tftypes.NewValue(tftypes.String, resp.Name),
+		"desc":          tftypes.NewValue(tftypes.String, resp.Desc),
+		"prov_url":      tftypes.NewValue(tftypes.String, resp.ProvUrl),
+		"prov_url_type": tftypes.NewValue(tftypes.String, resp.ProvUrlType),
+		"status":        tftypes.NewValue(tftypes.String, resp.Status),
+	}, nil
+}
@@ -0,0 +1,61 @@
+package functions
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// cidrContainsFunction reports whether an address or network lies within a network,
+// for checking source and destination IP group entries.
+func cidrContainsFunction() function {
+	return function{
+		definition: &tfprotov5.Function{
+			Summary:     "Checks whether a network contains an address or network",
+			Description: "Returns true when the IP address, or every address of the network in CIDR notation, lies within the containing network. Addresses of different IP versions are never contained.",
+			Parameters: []*tfprotov5.FunctionParameter{
+				stringParameter("cidr", "Containing network in CIDR notation, for example 10.0.0.0/8"),
+				stringParameter("address", "IP address or network in CIDR notation"),
+			},
+			Return: &tfprotov5.FunctionReturn{Type: tftypes.Bool},
+		},
+		call: func(args []tftypes.Value) (tftypes.Value, *tfprotov5.FunctionError) {
+			network, err := netip.ParsePrefix(strings.TrimSpace(stringArgument(args[0])))
+			if err != nil {
+				return tftypes.Value{}, argumentError(0, "%q is not a network in CIDR notation", stringArgument(args[0]))
+			}
+			inner, err := parseAddressOrPrefix(stringArgument(args[1]))
+			if err != nil {
+				return tftypes.Value{}, argumentError(1, "%s", err)
+			}
+			return tftypes.NewValue(tftypes.Bool, cidrContains(network, inner)), nil
+		},
+	}
+}
+
+func parseAddressOrPrefix(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("%q is not a network in CIDR notation", s)
+		}
+		return prefix, nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%q is not an IP address", s)
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func cidrContains(network, inner netip.Prefix) bool {
+	network, inner = network.Masked(), inner.Masked()
+	if network.Addr().Is4() != inner.Addr().Is4() {
+		return false
+	}
+	return inner.Bits() >= network.Bits() && network.Contains(inner.Addr())
+}
@@ -0,0 +1,56 @@
+package functions
+
+import "testing"
+
+func TestCIDRContains(t *testing.T) {
+	cases := []struct {
+		cidr, address string
+		want          bool
+	}{
+		{"10.0.0.0/8", "10.1.2.3", true},
+		{"10.0.0.0/8", "11.1.2.3", false},
+		{"10.0.0.0/8", "10.20.0.0/16", true},
+		{"10.20.0.0/16", "10.0.0.0/8", false},
+		{"10.0.0.0/8", "10.0.0.0/8", true},
+		{"10.1.2.3/8", "10.200.0.1", true},
+		{"192.168.1.0/24", "192.168.1.255", true},
+		{"2001:db8::/32", "2001:db8::1", true},
+		{"2001:db8::/32", "2001:db9::1", false},
+		{"0.0.0.0/0", "2001:db8::1", false},
+		{"::/0", "10.0.0.1", false},
+	}
+	for _, tc := range cases {
+		v, ferr := call(t, "cidr_contains", tc.cidr, tc.address)
+		if ferr != nil {
+			t.Errorf("cidr_contains(%q, %q): %s", tc.cidr, tc.address, ferr.Text)
+			continue
+		}
+		var got bool
+		_ = v.As(&got)
+		if got != tc.want {
+			t.Errorf("cidr_contains(%q, %q) = %t, want %t", tc.cidr, tc.address, got, tc.want)
+		}
+	}
+}
+
+func TestCIDRContains_Invalid(t *testing.T) {
+	cases := []struct {
+		cidr, address string
+		argument      int64
+	}{
+		{"10.0.0.1", "10.0.0.1", 0},
+		{"10.0.0.0/33", "10.0.0.1", 0},
+		{"10.0.0.0/8", "10.0.0", 1},
+		{"10.0.0.0/8", "10.0.0.0/40", 1},
+	}
+	for _, tc := range cases {
+		_, ferr := call(t, "cidr_contains", tc.cidr, tc.address)
+		if ferr == nil {
+			t.Errorf("cidr_contains(%q, %q): expected an error", tc.cidr, tc.address)
+			continue
+		}
+		if ferr.FunctionArgument == nil || *ferr.FunctionArgument != tc.argument {
+			t.Errorf("cidr_contains(%q, %q): error not attached to argument %d", tc.cidr, tc.address, tc.argument)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package functions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fabiotavarespr/iso3166"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// countryCodeFunction returns the ISO-3166 Alpha-2 code of a country as used by the
+// country arguments of the provider, from either the code itself or the COUNTRY_
+// prefixed value returned by the API.
+func countryCodeFunction() function {
+	return function{
+		definition: &tfprotov5.Function{
+			Summary:     "Returns the ISO-3166 Alpha-2 code of a country",
+			Description: "Strips the COUNTRY_ prefix used by the ZTC API, upper-cases the code and checks that it is a valid ISO-3166 Alpha-2 country code. ANY and NONE are returned unchanged.",
+			Parameters: []*tfprotov5.FunctionParameter{
+				stringParameter("country", "Country code, with or without the COUNTRY_ prefix, for example US or COUNTRY_US"),
+			},
+			Return: &tfprotov5.FunctionReturn{Type: tftypes.String},
+		},
+		call: func(args []tftypes.Value) (tftypes.Value, *tfprotov5.FunctionError) {
+			code, err := countryCode(stringArgument(args[0]))
+			if err != nil {
+				return tftypes.Value{}, argumentError(0, "%s", err)
+			}
+			return tftypes.NewValue(tftypes.String, code), nil
+		},
+	}
+}
+
+func countryCode(country string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(country))
+	code = strings.TrimPrefix(code, "COUNTRY_")
+	if code == "ANY" || code == "NONE" {
+		return code, nil
+	}
+	if !iso3166.ExistsIso3166ByAlpha2Code(code) {
+		return "", fmt.Errorf("%q is not a valid ISO-3166 Alpha-2 country code", country)
+	}
+	return code, nil
+}
@@ -0,0 +1,39 @@
+package functions
+
+import "testing"
+
+func TestCountryCode(t *testing.T) {
+	cases := map[string]string{
+		"US":         "US",
+		"COUNTRY_US": "US",
+		"country_ca": "CA",
+		" de ":       "DE",
+		"ANY":        "ANY",
+		"NONE":       "NONE",
+	}
+	for in, want := range cases {
+		v, ferr := call(t, "country_code", in)
+		if ferr != nil {
+			t.Errorf("country_code(%q): %s", in, ferr.Text)
+			continue
+		}
+		var got string
+		_ = v.As(&got)
+		if got != want {
+			t.Errorf("country_code(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCountryCode_Invalid(t *testing.T) {
+	for _, in := range []string{"", "XX", "USA", "COUNTRY_", "UNITED_STATES"} {
+		_, ferr := call(t, "country_code", in)
+		if ferr == nil {
+			t.Errorf("country_code(%q): expected an error", in)
+			continue
+		}
+		if ferr.FunctionArgument == nil || *ferr.FunctionArgument != 0 {
+			t.Errorf("country_code(%q): error not attached to the argument", in)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Package functions implements the provider-defined functions of the ZTC provider,
+// called in configuration as provider::ztc::<name>. They require Terraform 1.8 or later.
+package functions
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// function is a provider function definition and its implementation. Arguments are
+// passed in the order of the definition's parameters and are never null or unknown,
+// since no parameter allows them.
+type function struct {
+	definition *tfprotov5.Function
+	call       func(args []tftypes.Value) (tftypes.Value, *tfprotov5.FunctionError)
+}
+
+var functions = map[string]function{
+	"cidr_contains":         cidrContainsFunction(),
+	"country_code":          countryCodeFunction(),
+	"normalize_description": normalizeDescriptionFunction(),
+	"parse_ports":           parsePortsFunction(),
+}
+
+// Definitions returns the definitions of all provider functions by name.
+func Definitions() map[string]*tfprotov5.Function {
+	definitions := make(map[string]*tfprotov5.Function, len(functions))
+	for name, f := range functions {
+		definitions[name] = f.definition
+	}
+	return definitions
+}
+
+// Call runs the provider function name with its encoded arguments and returns its
+// encoded result.
+func Call(name string, arguments []*tfprotov5.DynamicValue) (*tfprotov5.DynamicValue, *tfprotov5.FunctionError) {
+	f, ok := functions[name]
+	if !ok {
+		return nil, &tfprotov5.FunctionError{Text: fmt.Sprintf("unknown function %q", name)}
+	}
+	params := f.definition.Parameters
+	if len(arguments) != len(params) {
+		return nil, &tfprotov5.FunctionError{Text: fmt.Sprintf("%s expects %d arguments, got %d", name, len(params), len(arguments))}
+	}
+
+	args := make([]tftypes.Value, len(arguments))
+	for i, argument := range arguments {
+		if argument == nil {
+			return nil, argumentError(i, "%s must not be null", params[i].Name)
+		}
+		v, err := argument.Unmarshal(params[i].Type)
+		if err != nil {
+			return nil, argumentError(i, "decoding %s: %s", params[i].Name, err)
+		}
+		if v.IsNull() || !v.IsFullyKnown() {
+			return nil, argumentError(i, "%s must be known and not null", params[i].Name)
+		}
+		args[i] = v
+	}
+
+	result, ferr := f.call(args)
+	if ferr != nil {
+		return nil, ferr
+	}
+	encoded, err := tfprotov5.NewDynamicValue(f.definition.Return.Type, result)
+	if err != nil {
+		return nil, &tfprotov5.FunctionError{Text: fmt.Sprintf("encoding the result of %s: %s", name, err)}
+	}
+	return &encoded, nil
+}
+
+func argumentError(i int, format string, a ...interface{}) *tfprotov5.FunctionError {
+	position := int64(i)
+	return &tfprotov5.FunctionError{
+		Text:             fmt.Sprintf(format, a...),
+		FunctionArgument: &position,
+	}
+}
+
+func stringParameter(name, description string) *tfprotov5.FunctionParameter {
+	return &tfprotov5.FunctionParameter{
+		Name:        name,
+		Description: description,
+		Type:        tftypes.String,
+	}
+}
+
+// stringArgument returns the value of a string parameter.
+func stringArgument(v tftypes.Value) string {
+	var s string
+	_ = v.As(&s)
+	return s
+}
@@ -0,0 +1,72 @@
+package functions
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// call runs the provider function name with string arguments and decodes its result.
+func call(t *testing.T, name string, args ...string) (tftypes.Value, *tfprotov5.FunctionError) {
+	t.Helper()
+	arguments := make([]*tfprotov5.DynamicValue, len(args))
+	for i, arg := range args {
+		v, err := tfprotov5.NewDynamicValue(tftypes.String, tftypes.NewValue(tftypes.String, arg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		arguments[i] = &v
+	}
+	result, ferr := Call(name, arguments)
+	if ferr != nil {
+		return tftypes.Value{}, ferr
+	}
+	v, err := result.Unmarshal(Definitions()[name].Return.Type)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v, nil
+}
+
+func TestDefinitions(t *testing.T) {
+	for name, definition := range Definitions() {
+		if definition.Summary == "" || definition.Description == "" {
+			t.Errorf("%s: missing summary or description", name)
+		}
+		if definition.Return == nil || definition.Return.Type == nil {
+			t.Errorf("%s: missing return type", name)
+		}
+		for _, param := range definition.Parameters {
+			if param.Name == "" || param.Type == nil {
+				t.Errorf("%s: parameter without a name or type", name)
+			}
+		}
+	}
+}
+
+func TestCall_UnknownFunction(t *testing.T) {
+	if _, ferr := Call("nope", nil); ferr == nil {
+		t.Fatal("expected an error for an unknown function")
+	}
+}
+
+func TestCall_ArgumentCount(t *testing.T) {
+	if _, ferr := call(t, "country_code"); ferr == nil {
+		t.Fatal("expected an error for a missing argument")
+	}
+	if _, ferr := call(t, "country_code", "US", "CA"); ferr == nil {
+		t.Fatal("expected an error for an extra argument")
+	}
+}
+
+func TestCall_NullArgument(t *testing.T) {
+	null, err := tfprotov5.NewDynamicValue(tftypes.String, tftypes.NewValue(tftypes.String, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ferr := Call("country_code", []*tfprotov5.DynamicValue{&null})
+	if ferr == nil || ferr.FunctionArgument == nil || *ferr.FunctionArgument != 0 {
+		t.Fatalf("expected an error on argument 0, got %+v", ferr)
+	}
+}
@@ -0,0 +1,31 @@
+package functions
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/zscaler/terraform-provider-ztc/ztc/common/multiline"
+)
+
+// normalizeDescriptionFunction trims a multi-line description the way the provider
+// does before comparing it with the API value, so heredoc indentation does not show
+// as a change. Unlike the value kept in state, `$` is not escaped and no trailing
+// newline is added.
+func normalizeDescriptionFunction() function {
+	return function{
+		definition: &tfprotov5.Function{
+			Summary:     "Normalizes a multi-line description",
+			Description: "Trims the whitespace around the text and around each of its lines, the same trimming the provider applies before comparing a description with the value stored by ZTC. Unlike the value kept in state, `$` is not escaped as `$$` and no trailing newline is added.",
+			Parameters: []*tfprotov5.FunctionParameter{
+				stringParameter("description", "Description text"),
+			},
+			Return: &tfprotov5.FunctionReturn{Type: tftypes.String},
+		},
+		call: func(args []tftypes.Value) (tftypes.Value, *tfprotov5.FunctionError) {
+			return tftypes.NewValue(tftypes.String, normalizeDescription(stringArgument(args[0]))), nil
+		},
+	}
+}
+
+func normalizeDescription(description string) string {
+	return multiline.TrimLines(description)
+}
@@ -0,0 +1,25 @@
+package functions
+
+import "testing"
+
+func TestNormalizeDescription(t *testing.T) {
+	cases := map[string]string{
+		"Branch":                          "Branch",
+		"  Branch  \n":                    "Branch",
+		"First line\n    Second line\n":   "First line\nSecond line",
+		"First line\r\n\tSecond line\r\n": "First line\nSecond line",
+		"Cost $${var.x}":                  "Cost $${var.x}",
+		"":                                "",
+	}
+	for in, want := range cases {
+		v, ferr := call(t, "normalize_description", in)
+		if ferr != nil {
+			t.Fatalf("normalize_description(%q): %s", in, ferr.Text)
+		}
+		var got string
+		_ = v.As(&got)
+		if got != want {
+			t.Errorf("normalize_description(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
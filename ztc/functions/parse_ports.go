@@ -0,0 +1,98 @@
+package functions
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var portRangeType = tftypes.Object{
+	AttributeTypes: map[string]tftypes.Type{
+		"start": tftypes.Number,
+		"end":   tftypes.Number,
+	},
+}
+
+// portRange is a port, or a range of ports when end is set, as in the port blocks of
+// ztc_network_services.
+type portRange struct {
+	start int
+	end   int
+}
+
+// parsePortsFunction returns the port blocks of a network service from a port list such
+// as "80, 443, 8000-8080".
+func parsePortsFunction() function {
+	return function{
+		definition: &tfprotov5.Function{
+			Summary:     "Parses a port list into network service port ranges",
+			Description: "Parses comma separated ports and port ranges, such as \"80, 443, 8000-8080\", into objects with start and end attributes for the port blocks of ztc_network_services. A single port has a null end.",
+			Parameters: []*tfprotov5.FunctionParameter{
+				stringParameter("ports", "Comma separated ports and port ranges"),
+			},
+			Return: &tfprotov5.FunctionReturn{Type: tftypes.List{ElementType: portRangeType}},
+		},
+		call: func(args []tftypes.Value) (tftypes.Value, *tfprotov5.FunctionError) {
+			ranges, err := parsePorts(stringArgument(args[0]))
+			if err != nil {
+				return tftypes.Value{}, argumentError(0, "%s", err)
+			}
+			values := make([]tftypes.Value, len(ranges))
+			for i, r := range ranges {
+				end := tftypes.NewValue(tftypes.Number, nil)
+				if r.end != 0 {
+					end = tftypes.NewValue(tftypes.Number, big.NewFloat(float64(r.end)))
+				}
+				values[i] = tftypes.NewValue(portRangeType, map[string]tftypes.Value{
+					"start": tftypes.NewValue(tftypes.Number, big.NewFloat(float64(r.start))),
+					"end":   end,
+				})
+			}
+			return tftypes.NewValue(tftypes.List{ElementType: portRangeType}, values), nil
+		},
+	}
+}
+
+func parsePorts(ports string) ([]portRange, error) {
+	var ranges []portRange
+	for _, item := range strings.Split(ports, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		start, end, isRange := strings.Cut(item, "-")
+		first, err := parsePort(start)
+		if err != nil {
+			return nil, err
+		}
+		if !isRange {
+			ranges = append(ranges, portRange{start: first})
+			continue
+		}
+		last, err := parsePort(end)
+		if err != nil {
+			return nil, err
+		}
+		if last < first {
+			return nil, fmt.Errorf("port range %q ends before it starts", item)
+		}
+		if last == first {
+			ranges = append(ranges, portRange{start: first})
+			continue
+		}
+		ranges = append(ranges, portRange{start: first, end: last})
+	}
+	return ranges, nil
+}
+
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("%q is not a port between 1 and 65535", strings.TrimSpace(s))
+	}
+	return port, nil
+}
@@ -0,0 +1,73 @@
+package functions
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestParsePorts(t *testing.T) {
+	cases := map[string][]portRange{
+		"80":                  {{start: 80}},
+		"80, 443":             {{start: 80}, {start: 443}},
+		"8000-8080":           {{start: 8000, end: 8080}},
+		" 22 , 1024 - 2048 ,": {{start: 22}, {start: 1024, end: 2048}},
+		"53-53":               {{start: 53}},
+		"":                    nil,
+		"1-65535":             {{start: 1, end: 65535}},
+	}
+	for in, want := range cases {
+		got, err := parsePorts(in)
+		if err != nil {
+			t.Errorf("parsePorts(%q): %s", in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("parsePorts(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestParsePorts_Invalid(t *testing.T) {
+	for _, in := range []string{"0", "65536", "http", "80-", "-80", "90-80", "1-2-3"} {
+		if _, ferr := call(t, "parse_ports", in); ferr == nil {
+			t.Errorf("parse_ports(%q): expected an error", in)
+		}
+	}
+}
+
+func TestParsePorts_Result(t *testing.T) {
+	v, ferr := call(t, "parse_ports", "443, 8000-8080")
+	if ferr != nil {
+		t.Fatal(ferr.Text)
+	}
+	var ranges []tftypes.Value
+	if err := v.As(&ranges); err != nil {
+		t.Fatal(err)
+	}
+	if len(ranges) != 2 {
+		t.Fatalf("got %d ranges, want 2", len(ranges))
+	}
+
+	var single map[string]tftypes.Value
+	if err := ranges[0].As(&single); err != nil {
+		t.Fatal(err)
+	}
+	var start big.Float
+	_ = single["start"].As(&start)
+	if start.Cmp(big.NewFloat(443)) != 0 || !single["end"].IsNull() {
+		t.Errorf("single port = %v, want start 443 and a null end", single)
+	}
+
+	var span map[string]tftypes.Value
+	if err := ranges[1].As(&span); err != nil {
+		t.Fatal(err)
+	}
+	var end big.Float
+	_ = span["end"].As(&end)
+	if end.Cmp(big.NewFloat(8080)) != 0 {
+		t.Errorf("range = %v, want end 8080", span)
+	}
+}
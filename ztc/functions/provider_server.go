@@ -0,0 +1,57 @@
+package functions
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+// providerServer is a protocol version 5 provider server that only serves the
+// provider functions, to be muxed with the SDKv2 provider server by tf5muxserver.
+// The mux routes resource, data source and ephemeral resource calls by type name,
+// so only the calls it sends to every server are implemented.
+type providerServer struct {
+	tfprotov5.ProviderServer
+}
+
+// NewProviderServer returns the provider server of the provider functions.
+func NewProviderServer() tfprotov5.ProviderServer {
+	return &providerServer{}
+}
+
+func (s *providerServer) GetMetadata(context.Context, *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	resp := &tfprotov5.GetMetadataResponse{}
+	for name := range functions {
+		resp.Functions = append(resp.Functions, tfprotov5.FunctionMetadata{Name: name})
+	}
+	return resp, nil
+}
+
+func (s *providerServer) GetProviderSchema(context.Context, *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	return &tfprotov5.GetProviderSchemaResponse{Functions: Definitions()}, nil
+}
+
+func (s *providerServer) GetResourceIdentitySchemas(context.Context, *tfprotov5.GetResourceIdentitySchemasRequest) (*tfprotov5.GetResourceIdentitySchemasResponse, error) {
+	return &tfprotov5.GetResourceIdentitySchemasResponse{}, nil
+}
+
+func (s *providerServer) PrepareProviderConfig(context.Context, *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
+	return &tfprotov5.PrepareProviderConfigResponse{}, nil
+}
+
+func (s *providerServer) ConfigureProvider(context.Context, *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	return &tfprotov5.ConfigureProviderResponse{}, nil
+}
+
+func (s *providerServer) StopProvider(context.Context, *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
+	return &tfprotov5.StopProviderResponse{}, nil
+}
+
+func (s *providerServer) GetFunctions(context.Context, *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	return &tfprotov5.GetFunctionsResponse{Functions: Definitions()}, nil
+}
+
+func (s *providerServer) CallFunction(_ context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	result, ferr := Call(req.Name, req.Arguments)
+	return &tfprotov5.CallFunctionResponse{Result: result, Error: ferr}, nil
+}
@@ -0,0 +1,89 @@
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// sdkServer stands in for the SDKv2 provider server.
+type sdkServer struct {
+	tfprotov5.ProviderServer
+}
+
+func (sdkServer) GetMetadata(context.Context, *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	return &tfprotov5.GetMetadataResponse{
+		Resources: []tfprotov5.ResourceMetadata{{TypeName: "ztc_ip_source_groups"}},
+	}, nil
+}
+
+func (sdkServer) GetProviderSchema(context.Context, *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	return &tfprotov5.GetProviderSchemaResponse{
+		ResourceSchemas: map[string]*tfprotov5.Schema{"ztc_ip_source_groups": {}},
+		Functions:       map[string]*tfprotov5.Function{},
+	}, nil
+}
+
+func (sdkServer) GetFunctions(context.Context, *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	return &tfprotov5.GetFunctionsResponse{Functions: map[string]*tfprotov5.Function{}}, nil
+}
+
+func TestProviderServer(t *testing.T) {
+	ctx := context.Background()
+	mux, err := tf5muxserver.NewMuxServer(ctx, func() tfprotov5.ProviderServer { return sdkServer{} }, NewProviderServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := mux.ProviderServer()
+
+	metadata, err := server.GetMetadata(ctx, &tfprotov5.GetMetadataRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metadata.Resources) != 1 || len(metadata.Functions) != len(functions) {
+		t.Errorf("metadata has %d resources and %d functions", len(metadata.Resources), len(metadata.Functions))
+	}
+
+	schema, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schema.ResourceSchemas) != 1 || schema.Functions["parse_ports"] == nil {
+		t.Errorf("schema has %d resources and functions %v", len(schema.ResourceSchemas), schema.Functions)
+	}
+
+	fns, err := server.GetFunctions(ctx, &tfprotov5.GetFunctionsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fns.Functions) != len(functions) {
+		t.Errorf("got %d functions, want %d", len(fns.Functions), len(functions))
+	}
+
+	arg, err := tfprotov5.NewDynamicValue(tftypes.String, tftypes.NewValue(tftypes.String, "COUNTRY_FR"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.CallFunction(ctx, &tfprotov5.CallFunctionRequest{
+		Name:      "country_code",
+		Arguments: []*tfprotov5.DynamicValue{&arg},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error != nil {
+		t.Fatal(resp.Error.Text)
+	}
+	v, err := resp.Result.Unmarshal(tftypes.String)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var code string
+	_ = v.As(&code)
+	if code != "FR" {
+		t.Errorf("country_code returned %q, want FR", code)
+	}
+}
@@ -0,0 +1,138 @@
+package ztc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// newHTTPTransport builds the dedicated transport used for every API call. It applies
+// the proxy (including credentials, https:// proxies and no_proxy), the extra CA bundle
+// and the optional client certificate.
+func (c *Config) newHTTPTransport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	proxy, err := c.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	transport.Proxy = proxy
+
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// proxyFunc returns the proxy selection function. Without http_proxy the standard
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables apply.
+func (c *Config) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	proxyConfig := httpproxy.FromEnvironment()
+	if c.noProxy != "" {
+		proxyConfig.NoProxy = c.noProxy
+	}
+
+	if c.httpProxy != "" {
+		proxyURL, err := parseProxyURL(c.httpProxy)
+		if err != nil {
+			return nil, err
+		}
+		if c.proxyUsername != "" {
+			proxyURL.User = url.UserPassword(c.proxyUsername, c.proxyPassword)
+		}
+		proxyConfig.HTTPProxy = proxyURL.String()
+		proxyConfig.HTTPSProxy = proxyURL.String()
+	}
+
+	proxyForURL := proxyConfig.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyForURL(req.URL)
+	}, nil
+}
+
+// parseProxyURL validates a proxy of scheme://hostname or scheme://hostname:port format.
+func parseProxyURL(raw string) (*url.URL, error) {
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %v", err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", raw)
+	}
+	if proxyURL.Hostname() == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing hostname", raw)
+	}
+	if sPort := proxyURL.Port(); sPort != "" {
+		port, err := strconv.Atoi(sPort)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy port: %v", err)
+		}
+		if port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid port number: must be between 1 and 65535, got: %d", port)
+		}
+	}
+	return proxyURL, nil
+}
+
+// tlsConfig returns the TLS settings: the system roots plus ca_bundle/ca_bundle_file,
+// and the client certificate when one is configured.
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	caBundle := []byte(c.caBundle)
+	if c.caBundleFile != "" {
+		data, err := os.ReadFile(c.caBundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading ca_bundle_file: %w", err)
+		}
+		caBundle = append(caBundle, '\n')
+		caBundle = append(caBundle, data...)
+	}
+	if len(caBundle) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("ca_bundle does not contain any valid PEM certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	certPEM, keyPEM := []byte(c.clientCertificate), []byte(c.clientKey)
+	if c.clientCertificateFile != "" {
+		data, err := os.ReadFile(c.clientCertificateFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading client_certificate_file: %w", err)
+		}
+		certPEM = data
+	}
+	if c.clientKeyFile != "" {
+		key, err := readSecretFile(c.clientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("client_key_file: %w", err)
+		}
+		keyPEM = []byte(key)
+	}
+	if len(certPEM) > 0 || len(keyPEM) > 0 {
+		if len(certPEM) == 0 || len(keyPEM) == 0 {
+			return nil, fmt.Errorf("a client certificate requires both the certificate and its private key")
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
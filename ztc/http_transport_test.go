@@ -0,0 +1,68 @@
+package ztc
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestConfigProxyFunc(t *testing.T) {
+	c := &Config{
+		httpProxy:     "https://proxy.example.com:8443",
+		proxyUsername: "alice",
+		proxyPassword: "p@ss",
+		noProxy:       "internal.example.com,10.0.0.0/8",
+	}
+	proxy, err := c.proxyFunc()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest("GET", "https://api.zsapi.net/ztw/api/v1/ecRules/ecRdr", nil)
+	u, err := proxy(req)
+	if err != nil || u == nil {
+		t.Fatalf("expected a proxy, got %v, %v", u, err)
+	}
+	if u.Scheme != "https" || u.Host != "proxy.example.com:8443" {
+		t.Fatalf("unexpected proxy %s", u.Redacted())
+	}
+	if password, _ := u.User.Password(); u.User.Username() != "alice" || password != "p@ss" {
+		t.Fatalf("proxy credentials were not applied: %s", u.Redacted())
+	}
+
+	for _, target := range []string{"https://internal.example.com/api", "https://10.1.2.3/api"} {
+		req, _ := http.NewRequest("GET", target, nil)
+		if u, _ := proxy(req); u != nil {
+			t.Errorf("%s should bypass the proxy, got %s", target, u.Redacted())
+		}
+	}
+}
+
+func TestParseProxyURL(t *testing.T) {
+	for _, raw := range []string{"http://proxy:3128", "https://proxy", "socks5://proxy:1080"} {
+		if _, err := parseProxyURL(raw); err != nil {
+			t.Errorf("%s: unexpected error: %v", raw, err)
+		}
+	}
+	for _, raw := range []string{"ftp://proxy", "http://proxy:70000", "http://"} {
+		if _, err := parseProxyURL(raw); err == nil {
+			t.Errorf("%s: expected an error", raw)
+		}
+	}
+}
+
+func TestConfigTLSConfig(t *testing.T) {
+	if _, err := (&Config{caBundle: "not a certificate"}).tlsConfig(); err == nil || !strings.Contains(err.Error(), "PEM") {
+		t.Fatalf("expected an invalid CA bundle error, got %v", err)
+	}
+	if _, err := (&Config{clientCertificate: "cert"}).tlsConfig(); err == nil {
+		t.Fatal("expected an error for a client certificate without key")
+	}
+	tlsConfig, err := (&Config{}).tlsConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tlsConfig.RootCAs != nil || len(tlsConfig.Certificates) != 0 {
+		t.Fatal("expected the system defaults when nothing is configured")
+	}
+}
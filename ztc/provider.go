@@ -4,15 +4,15 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
 )
 
-var apiSemaphore chan struct{}
-
 func ZTCProvider() *schema.Provider {
 	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
@@ -35,6 +35,37 @@ func ZTCProvider() *schema.Provider {
 				Description:   "zpa private key",
 				ConflictsWith: []string{"client_secret"},
 			},
+			"client_secret_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a file containing the client secret. The file must only be readable by its owner",
+				ConflictsWith: []string{"client_secret", "private_key", "private_key_file", "credential_process"},
+			},
+			"private_key_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a file containing the private key. The file must only be readable by its owner",
+				ConflictsWith: []string{"private_key", "client_secret", "client_secret_file", "credential_process"},
+			},
+			"credential_process": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Command that prints the credentials as a JSON object on stdout",
+				ConflictsWith: []string{"client_secret", "private_key", "client_secret_file", "private_key_file", "oidc_token", "oidc_token_file"},
+			},
+			"oidc_token": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				Description:   "OIDC federated token used as the client assertion when requesting OneAPI tokens",
+				ConflictsWith: []string{"oidc_token_file", "client_secret", "private_key", "credential_process"},
+			},
+			"oidc_token_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a file containing the OIDC federated token. It is read again on every token request",
+				ConflictsWith: []string{"oidc_token", "client_secret", "private_key", "credential_process"},
+			},
 			"vanity_domain": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -84,7 +115,74 @@ func ZTCProvider() *schema.Provider {
 			"http_proxy": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Alternate HTTP proxy of scheme://hostname or scheme://hostname:port format",
+				Description: "Alternate HTTP proxy of scheme://hostname or scheme://hostname:port format. http, https and socks5 schemes are supported",
+			},
+			"tenants": tenantsSchema(),
+			"default_tenant": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Name of the tenants block used by resources and data sources that do not set `tenant`. When not set, they use the top-level credentials",
+			},
+			"proxy_username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Username for an authenticating proxy",
+			},
+			"proxy_password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password for an authenticating proxy",
+			},
+			"no_proxy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Comma-separated list of hosts, domains and CIDRs that bypass the proxy, in NO_PROXY format",
+			},
+			"ca_bundle": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PEM encoded CA certificates trusted in addition to the system roots",
+			},
+			"ca_bundle_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to a PEM file with CA certificates trusted in addition to the system roots",
+			},
+			"client_certificate": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "PEM encoded client certificate presented on TLS connections",
+				ConflictsWith: []string{"client_certificate_file"},
+			},
+			"client_certificate_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a PEM encoded client certificate presented on TLS connections",
+				ConflictsWith: []string{"client_certificate"},
+			},
+			"client_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				Description:   "PEM encoded private key of the client certificate",
+				ConflictsWith: []string{"client_key_file"},
+			},
+			"client_key_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to the PEM encoded private key of the client certificate. The file must only be readable by its owner",
+				ConflictsWith: []string{"client_key"},
+			},
+			"audit_log_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path of a file to which every API call is appended as a JSON line (method, endpoint, resource, status, latency, retry count and error code). Secrets and tokens are never logged",
+			},
+			"token_cache_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Directory in which OneAPI access tokens are cached, encrypted with the client secret or private key, so that provider runs reuse a valid token instead of authenticating again",
 			},
 			"max_retries": {
 				Type:             schema.TypeInt,
@@ -95,7 +193,13 @@ func ZTCProvider() *schema.Provider {
 			"parallelism": {
 				Type:        schema.TypeInt,
 				Optional:    true,
-				Description: "Number of concurrent requests to make within a resource where bulk operations are not possible. Take note of https://help.zscaler.com/oneapi/understanding-rate-limiting.",
+				Description: "Maximum number of concurrent API requests per endpoint family (rules, groups, gateways, activation). The default is `0` (no limit). Take note of https://help.zscaler.com/oneapi/understanding-rate-limiting.",
+			},
+			"max_requests_per_second": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: intBetween(0, 100),
+				Description:      "Maximum number of API requests started per second for each endpoint family. The default is `0` (no limit besides `parallelism`).",
 			},
 			"request_timeout": {
 				Type:             schema.TypeInt,
@@ -110,6 +214,7 @@ func ZTCProvider() *schema.Provider {
 			"ztc_location_template":           resourceLocationTemplate(),
 			"ztc_provisioning_url":            resourceProvisioningURL(),
 			"ztc_traffic_forwarding_rule":     resourceTrafficForwardingRule(),
+			"ztc_traffic_forwarding_policy":   resourceTrafficForwardingPolicy(),
 			"ztc_traffic_forwarding_dns_rule": resourceTrafficForwardingDNSRule(),
 			"ztc_traffic_forwarding_log_rule": resourceTrafficForwardingLogRule(),
 			"ztc_forwarding_gateway":          resourceForwardingGateway(),
@@ -122,6 +227,8 @@ func ZTCProvider() *schema.Provider {
 			"ztc_account_groups":              resourceAccountGroup(),
 			"ztc_public_cloud_info":           resourcePublicCloudInfo(),
 			"ztc_dns_gateway":                 resourceDNSGateway(),
+			"ztc_rule_state_override":         resourceRuleStateOverride(),
+			"ztc_workload_groups":             resourceWorkloadGroups(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
@@ -130,6 +237,7 @@ func ZTCProvider() *schema.Provider {
 			"ztc_provisioning_url":            dataSourceProvisioningURL(),
 			"ztc_location_management":         dataSourceLocationManagement(),
 			"ztc_edge_connector_group":        dataSourceEdgeConnectorGroup(),
+			"ztc_edge_connector_fleet":        dataSourceEdgeConnectorFleet(),
 			"ztc_traffic_forwarding_rule":     dataSourceTrafficForwardingRule(),
 			"ztc_traffic_forwarding_dns_rule": dataSourceTrafficForwardingDNSRule(),
 			"ztc_traffic_forwarding_log_rule": dataSourceTrafficForwardingLogRule(),
@@ -148,7 +256,18 @@ func ZTCProvider() *schema.Provider {
 		},
 	}
 
-	p.ConfigureContextFunc = func(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	for name, r := range p.ResourcesMap {
+		if name != "ztc_activation_status" {
+			withChangeSet(name, r)
+		}
+		withAudit(name, r, false)
+	}
+	for name, r := range p.DataSourcesMap {
+		withAudit(name, r, true)
+	}
+	withTenants(p)
+
+	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		terraformVersion := p.TerraformVersion
 		if terraformVersion == "" {
 			// Terraform 0.12 introduced this field to the protocol
@@ -166,6 +285,15 @@ func ZTCProvider() *schema.Provider {
 				},
 			}
 		}
+		// The audit log file is kept open until Terraform stops the provider
+		if client, ok := r.(*Client); ok && client.auditLog != nil {
+			if stop, ok := schema.StopContext(ctx); ok {
+				go func() {
+					<-stop.Done()
+					_ = client.auditLog.close()
+				}()
+			}
+		}
 		return r, nil
 	}
 
@@ -173,30 +301,56 @@ func ZTCProvider() *schema.Provider {
 }
 
 func providerConfigure(d *schema.ResourceData, terraformVersion string) (interface{}, diag.Diagnostics) {
+	return providerConfigureWithTransport(d, terraformVersion, nil)
+}
+
+// providerConfigureWithTransport configures the provider and sends all API calls through
+// transport when it is not nil. Acceptance tests use it to record and replay API traffic.
+func providerConfigureWithTransport(d *schema.ResourceData, terraformVersion string, transport http.RoundTripper) (interface{}, diag.Diagnostics) {
 	log.Printf("[INFO] Initializing Zscaler client")
 
 	// Create configuration from schema
 	config := NewConfig(d)
 	config.TerraformVersion = terraformVersion
-
-	// Load the correct SDK client (prioritizing V3)
-	if diags := config.loadClients(); diags.HasError() {
-		return nil, diags
+	config.httpTransport = transport
+	if config.auditLogFile != "" {
+		config.auditLog = &auditLog{path: config.auditLogFile, base: config.logger}
 	}
 
-	// Return the configured client
-	client, err := config.Client()
-	if err != nil {
-		return nil, diag.Errorf("failed to configure Zscaler client: %v", err)
+	defaultTenant := d.Get("default_tenant").(string)
+	tenants, _ := d.Get("tenants").([]interface{})
+
+	// The top-level credentials are optional when every resource can use a tenant
+	client := &Client{}
+	if defaultTenant == "" && (len(tenants) == 0 || config.hasCredentials()) {
+		var err error
+		client, err = config.Client()
+		if err != nil {
+			return nil, diag.Errorf("failed to configure Zscaler client: %v", err)
+		}
 	}
 
-	// Initialize the global semaphore based on the configured parallelism
-	if config.parallelism > 0 {
-		apiSemaphore = make(chan struct{}, config.parallelism)
-	} else {
-		apiSemaphore = make(chan struct{}, 1)
+	client.auditLog = config.auditLog
+	if err := configureTenants(d, config, client); err != nil {
+		return nil, diag.FromErr(err)
+	}
+	if defaultTenant != "" {
+		tenantClient, err := client.forTenant(defaultTenant)
+		if err != nil {
+			return nil, diag.Errorf("invalid default_tenant: %v", err)
+		}
+		client.newService = func() (*zscaler.Service, error) {
+			if err := tenantClient.connect(); err != nil {
+				return nil, err
+			}
+			return tenantClient.Service, nil
+		}
+		client.changes = tenantClient.changes
+		client.rules = tenantClient.rules
+		client.legacy = tenantClient.legacy
 	}
 
+	// Return the configured client
 	return client, nil
 }
 
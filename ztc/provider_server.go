@@ -0,0 +1,206 @@
+package ztc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/terraform-provider-ztc/ztc/functions"
+)
+
+// ephemeralResource is an ephemeral resource (Terraform 1.10+), which the SDKv2 cannot
+// define. open receives the known configuration and the configured provider meta, and
+// returns the values of the computed attributes; its result is never stored in state.
+type ephemeralResource struct {
+	schema   *tfprotov5.Schema
+	validate func(config map[string]tftypes.Value) []*tfprotov5.Diagnostic
+	open     func(ctx context.Context, config map[string]tftypes.Value, meta interface{}) (map[string]tftypes.Value, []*tfprotov5.Diagnostic)
+}
+
+func ephemeralResources() map[string]ephemeralResource {
+	return map[string]ephemeralResource{
+		"ztc_provisioning_url": ephemeralProvisioningURL(),
+	}
+}
+
+// providerServer serves the SDKv2 provider, muxed with the provider functions, with
+// the ephemeral resources added. Ephemeral resources use the client configured by the
+// SDKv2 provider.
+type providerServer struct {
+	tfprotov5.ProviderServer
+	provider           *schema.Provider
+	ephemeralResources map[string]ephemeralResource
+}
+
+// ProviderServer returns the protocol version 5 server of the provider.
+func ProviderServer() (tfprotov5.ProviderServer, error) {
+	p := ZTCProvider()
+	mux, err := tf5muxserver.NewMuxServer(context.Background(),
+		func() tfprotov5.ProviderServer { return schema.NewGRPCProviderServer(p) },
+		functions.NewProviderServer,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return newProviderServer(p, mux.ProviderServer()), nil
+}
+
+func newProviderServer(p *schema.Provider, server tfprotov5.ProviderServer) *providerServer {
+	return &providerServer{
+		ProviderServer:     server,
+		provider:           p,
+		ephemeralResources: ephemeralResources(),
+	}
+}
+
+func (s *providerServer) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	resp, err := s.ProviderServer.GetMetadata(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	for name := range s.ephemeralResources {
+		resp.EphemeralResources = append(resp.EphemeralResources, tfprotov5.EphemeralResourceMetadata{TypeName: name})
+	}
+	return resp, nil
+}
+
+func (s *providerServer) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp, err := s.ProviderServer.GetProviderSchema(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	if resp.EphemeralResourceSchemas == nil {
+		resp.EphemeralResourceSchemas = map[string]*tfprotov5.Schema{}
+	}
+	for name, r := range s.ephemeralResources {
+		resp.EphemeralResourceSchemas[name] = r.schema
+	}
+	return resp, nil
+}
+
+func (s *providerServer) ValidateEphemeralResourceConfig(ctx context.Context, req *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error) {
+	r, ok := s.ephemeralResources[req.TypeName]
+	if !ok {
+		return s.ProviderServer.ValidateEphemeralResourceConfig(ctx, req)
+	}
+	config, diags := decodeEphemeralConfig(r.schema, req.Config)
+	if config != nil && r.validate != nil {
+		diags = r.validate(config)
+	}
+	return &tfprotov5.ValidateEphemeralResourceConfigResponse{Diagnostics: diags}, nil
+}
+
+func (s *providerServer) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	r, ok := s.ephemeralResources[req.TypeName]
+	if !ok {
+		return s.ProviderServer.OpenEphemeralResource(ctx, req)
+	}
+	config, diags := decodeEphemeralConfig(r.schema, req.Config)
+	if diags != nil {
+		return &tfprotov5.OpenEphemeralResourceResponse{Diagnostics: diags}, nil
+	}
+	if config == nil {
+		unknown, err := tfprotov5.NewDynamicValue(r.schema.ValueType(), tftypes.NewValue(r.schema.ValueType(), tftypes.UnknownValue))
+		if err != nil {
+			return &tfprotov5.OpenEphemeralResourceResponse{Diagnostics: ephemeralError(req.TypeName, err)}, nil
+		}
+		return &tfprotov5.OpenEphemeralResourceResponse{Result: &unknown}, nil
+	}
+	meta := s.provider.Meta()
+	if meta == nil {
+		return &tfprotov5.OpenEphemeralResourceResponse{
+			Diagnostics: ephemeralError(req.TypeName, fmt.Errorf("the provider is not configured")),
+		}, nil
+	}
+
+	values, diags := r.open(ctx, config, meta)
+	if hasEphemeralError(diags) {
+		return &tfprotov5.OpenEphemeralResourceResponse{Diagnostics: diags}, nil
+	}
+	for name, v := range values {
+		config[name] = v
+	}
+	result, err := tfprotov5.NewDynamicValue(r.schema.ValueType(), tftypes.NewValue(r.schema.ValueType(), config))
+	if err != nil {
+		return &tfprotov5.OpenEphemeralResourceResponse{
+			Diagnostics: append(diags, ephemeralError(req.TypeName, err)...),
+		}, nil
+	}
+	return &tfprotov5.OpenEphemeralResourceResponse{Result: &result, Diagnostics: diags}, nil
+}
+
+func (s *providerServer) RenewEphemeralResource(ctx context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+	if _, ok := s.ephemeralResources[req.TypeName]; !ok {
+		return s.ProviderServer.RenewEphemeralResource(ctx, req)
+	}
+	return &tfprotov5.RenewEphemeralResourceResponse{}, nil
+}
+
+func (s *providerServer) CloseEphemeralResource(ctx context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+	if _, ok := s.ephemeralResources[req.TypeName]; !ok {
+		return s.ProviderServer.CloseEphemeralResource(ctx, req)
+	}
+	return &tfprotov5.CloseEphemeralResourceResponse{}, nil
+}
+
+// decodeEphemeralConfig returns the attribute values of an ephemeral resource
+// configuration. It returns nil values and no diagnostics while the configuration is
+// not yet fully known.
+func decodeEphemeralConfig(s *tfprotov5.Schema, config *tfprotov5.DynamicValue) (map[string]tftypes.Value, []*tfprotov5.Diagnostic) {
+	if config == nil {
+		return nil, ephemeralError("configuration", fmt.Errorf("missing configuration"))
+	}
+	v, err := config.Unmarshal(s.ValueType())
+	if err != nil {
+		return nil, ephemeralError("configuration", err)
+	}
+	if !v.IsFullyKnown() {
+		return nil, nil
+	}
+	values := map[string]tftypes.Value{}
+	if err := v.As(&values); err != nil {
+		return nil, ephemeralError("configuration", err)
+	}
+	return values, nil
+}
+
+func ephemeralError(summary string, err error) []*tfprotov5.Diagnostic {
+	return []*tfprotov5.Diagnostic{{
+		Severity: tfprotov5.DiagnosticSeverityError,
+		Summary:  summary,
+		Detail:   err.Error(),
+	}}
+}
+
+func hasEphemeralError(diags []*tfprotov5.Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func ephemeralString(v tftypes.Value) string {
+	var s string
+	if v.IsKnown() && !v.IsNull() {
+		_ = v.As(&s)
+	}
+	return s
+}
+
+func ephemeralInt(v tftypes.Value) (int, bool) {
+	if !v.IsKnown() || v.IsNull() {
+		return 0, false
+	}
+	var f big.Float
+	if err := v.As(&f); err != nil {
+		return 0, false
+	}
+	n, _ := f.Int64()
+	return int(n), true
+}
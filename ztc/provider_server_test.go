@@ -0,0 +1,136 @@
+package ztc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func ephemeralConfig(t *testing.T, typeName string, values map[string]tftypes.Value) *tfprotov5.DynamicValue {
+	t.Helper()
+	s := ephemeralResources()[typeName].schema
+	for _, attr := range s.Block.Attributes {
+		if _, ok := values[attr.Name]; !ok {
+			values[attr.Name] = tftypes.NewValue(attr.Type, nil)
+		}
+	}
+	config, err := tfprotov5.NewDynamicValue(s.ValueType(), tftypes.NewValue(s.ValueType(), values))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &config
+}
+
+func testProviderServer(t *testing.T) tfprotov5.ProviderServer {
+	t.Helper()
+	server, err := ProviderServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server
+}
+
+func TestProviderServer_EphemeralResources(t *testing.T) {
+	ctx := context.Background()
+	server := testProviderServer(t)
+
+	metadata, err := server.GetMetadata(ctx, &tfprotov5.GetMetadataRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, r := range metadata.EphemeralResources {
+		found = found || r.TypeName == "ztc_provisioning_url"
+	}
+	if !found || len(metadata.Functions) == 0 || len(metadata.Resources) == 0 {
+		t.Errorf("metadata is missing ephemeral resources, functions or resources: %+v", metadata)
+	}
+
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := schemas.EphemeralResourceSchemas["ztc_provisioning_url"]
+	if s == nil {
+		t.Fatal("missing ztc_provisioning_url ephemeral resource schema")
+	}
+	for _, attr := range s.Block.Attributes {
+		if attr.Name == "prov_url" && !attr.Sensitive {
+			t.Error("prov_url must be sensitive")
+		}
+	}
+	if schemas.ResourceSchemas["ztc_provisioning_url"] == nil {
+		t.Error("the ztc_provisioning_url resource must still be served")
+	}
+}
+
+func TestProviderServer_ValidateProvisioningURL(t *testing.T) {
+	ctx := context.Background()
+	server := testProviderServer(t)
+
+	resp, err := server.ValidateEphemeralResourceConfig(ctx, &tfprotov5.ValidateEphemeralResourceConfigRequest{
+		TypeName: "ztc_provisioning_url",
+		Config:   ephemeralConfig(t, "ztc_provisioning_url", map[string]tftypes.Value{}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasEphemeralError(resp.Diagnostics) {
+		t.Error("expected an error without id or name")
+	}
+
+	resp, err = server.ValidateEphemeralResourceConfig(ctx, &tfprotov5.ValidateEphemeralResourceConfigRequest{
+		TypeName: "ztc_provisioning_url",
+		Config: ephemeralConfig(t, "ztc_provisioning_url", map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "AWS-Prov-URL"),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasEphemeralError(resp.Diagnostics) {
+		t.Errorf("unexpected diagnostics: %+v", resp.Diagnostics[0])
+	}
+}
+
+func TestProviderServer_OpenProvisioningURL(t *testing.T) {
+	ctx := context.Background()
+	server := testProviderServer(t)
+
+	// An unknown name, as during a plan, gives an unknown result without an API call.
+	resp, err := server.OpenEphemeralResource(ctx, &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: "ztc_provisioning_url",
+		Config: ephemeralConfig(t, "ztc_provisioning_url", map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasEphemeralError(resp.Diagnostics) || resp.Result == nil {
+		t.Fatalf("expected an unknown result, got %+v", resp.Diagnostics)
+	}
+	v, err := resp.Result.Unmarshal(ephemeralResources()["ztc_provisioning_url"].schema.ValueType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.IsKnown() {
+		t.Error("expected an unknown result")
+	}
+
+	// The provider has not been configured.
+	resp, err = server.OpenEphemeralResource(ctx, &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: "ztc_provisioning_url",
+		Config: ephemeralConfig(t, "ztc_provisioning_url", map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "AWS-Prov-URL"),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasEphemeralError(resp.Diagnostics) || resp.Result != nil {
+		t.Error("expected an error before the provider is configured")
+	}
+}
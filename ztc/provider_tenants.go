@@ -0,0 +1,270 @@
+package ztc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// tenantsSchema describes the named credential sets a single provider configuration can
+// manage in addition to (or instead of) the top-level credentials.
+func tenantsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Named credential sets. Resources and data sources select one with their `tenant` argument.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringIsNotWhiteSpace,
+					Description:  "Name used by the `tenant` argument of resources and data sources",
+				},
+				"client_id": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "OneAPI client id",
+				},
+				"client_secret": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "OneAPI client secret",
+				},
+				"private_key": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "OneAPI private key",
+				},
+				"vanity_domain": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "Zscaler Vanity Domain",
+				},
+				"zscaler_cloud": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Zscaler Cloud Name, e.g. `beta`",
+				},
+				"username": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Legacy API administrator username",
+				},
+				"password": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "Legacy API administrator password",
+				},
+				"api_key": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "Legacy API key",
+				},
+				"ztc_cloud": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Legacy API cloud name, e.g. `zscalerbeta` or `zscalergov`",
+				},
+				"use_legacy_client": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Use the legacy API framework for this tenant",
+				},
+			},
+		},
+	}
+}
+
+// newTenantConfig returns a copy of the provider configuration that uses the credentials
+// of a single tenants block. Connection settings such as retries, parallelism and proxy
+// are inherited; each tenant gets its own rate limiter.
+func newTenantConfig(base *Config, m map[string]interface{}) *Config {
+	c := *base
+	c.clientID, _ = m["client_id"].(string)
+	c.clientSecret, _ = m["client_secret"].(string)
+	c.privateKey, _ = m["private_key"].(string)
+	c.vanityDomain, _ = m["vanity_domain"].(string)
+	c.cloud, _ = m["zscaler_cloud"].(string)
+	c.Username, _ = m["username"].(string)
+	c.Password, _ = m["password"].(string)
+	c.APIKey, _ = m["api_key"].(string)
+	c.ZTCBaseURL, _ = m["ztc_cloud"].(string)
+	c.useLegacyClient, _ = m["use_legacy_client"].(bool)
+	c.clientSecretFile, c.privateKeyFile, c.credentialProcess, c.oidcToken, c.oidcTokenFile = "", "", "", "", ""
+	c.limiter = newAPILimiter(c.parallelism, c.requestsPerSecond)
+	c.auditTenant, _ = m["name"].(string)
+	return &c
+}
+
+// configureTenants builds one client per tenants block and attaches them to client.
+func configureTenants(d *schema.ResourceData, base *Config, client *Client) error {
+	tenants, _ := d.Get("tenants").([]interface{})
+	for _, t := range tenants {
+		m, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := m["name"].(string)
+		if _, exists := client.tenants[name]; exists {
+			return fmt.Errorf("tenant %q is defined more than once", name)
+		}
+		tenantClient, err := newTenantConfig(base, m).Client()
+		if err != nil {
+			return fmt.Errorf("failed to configure tenant %q: %w", name, err)
+		}
+		tenantClient.tenantName = name
+		if client.tenants == nil {
+			client.tenants = map[string]*Client{}
+		}
+		client.tenants[name] = tenantClient
+	}
+	return nil
+}
+
+// forTenant returns the client of the named tenant, or the provider's default client
+// when name is empty.
+func (c *Client) forTenant(name string) (*Client, error) {
+	if name == "" {
+		// newService never changes after configuration, while connect may be setting Service
+		if c.newService == nil && c.Service == nil {
+			return nil, fmt.Errorf("no default credentials are configured; set the tenant argument or the provider default_tenant")
+		}
+		return c, nil
+	}
+	if tenant, ok := c.tenants[name]; ok {
+		return tenant, nil
+	}
+	return nil, fmt.Errorf("tenant %q is not defined in the provider tenants block", name)
+}
+
+// tenantNames returns the configured tenant names, used in error messages.
+func (c *Client) tenantNames() []string {
+	names := make([]string, 0, len(c.tenants))
+	for name := range c.tenants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func tenantArgumentSchema(forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    forceNew,
+		Description: "Name of the provider `tenants` block whose credentials are used. Defaults to the provider's default credentials",
+	}
+}
+
+// withTenant adds the tenant argument to a resource or data source and wraps its
+// functions so that they receive the client of the selected tenant as meta. Resource
+// imports accept an optional "<tenant>/" prefix on the ID.
+func withTenant(r *schema.Resource, isDataSource bool) *schema.Resource {
+	r.Schema["tenant"] = tenantArgumentSchema(!isDataSource)
+
+	wrap := func(f schema.CreateContextFunc) schema.CreateContextFunc {
+		if f == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			tenantMeta, err := tenantMetaFor(meta, d.Get("tenant").(string))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			return f(ctx, d, tenantMeta)
+		}
+	}
+	r.CreateContext = wrap(r.CreateContext)
+	r.ReadContext = schema.ReadContextFunc(wrap(schema.CreateContextFunc(r.ReadContext)))
+	r.UpdateContext = schema.UpdateContextFunc(wrap(schema.CreateContextFunc(r.UpdateContext)))
+	r.DeleteContext = schema.DeleteContextFunc(wrap(schema.CreateContextFunc(r.DeleteContext)))
+
+	if customizeDiff := r.CustomizeDiff; customizeDiff != nil {
+		r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			tenantMeta, err := selectTenantMeta(meta, d.Get("tenant").(string))
+			if err != nil {
+				return err
+			}
+			return customizeDiff(ctx, d, tenantMeta)
+		}
+	}
+
+	if r.Importer != nil && r.Importer.StateContext != nil {
+		importState := r.Importer.StateContext
+		r.Importer.StateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			tenant := ""
+			if client, ok := meta.(*Client); ok {
+				if prefix, id, found := strings.Cut(d.Id(), "/"); found {
+					if _, known := client.tenants[prefix]; known {
+						tenant = prefix
+						d.SetId(id)
+					}
+				}
+			}
+			_ = d.Set("tenant", tenant)
+			tenantMeta, err := tenantMetaFor(meta, tenant)
+			if err != nil {
+				return nil, err
+			}
+			return importState(ctx, d, tenantMeta)
+		}
+	}
+	return r
+}
+
+// tenantMetaFor returns the client of the selected tenant as meta, connected to the API.
+func tenantMetaFor(meta interface{}, tenant string) (interface{}, error) {
+	tenantMeta, err := selectTenantMeta(meta, tenant)
+	if err != nil {
+		return nil, err
+	}
+	client, ok := tenantMeta.(*Client)
+	if !ok {
+		return tenantMeta, nil
+	}
+	if err := client.connect(); err != nil {
+		if client.tenantName != "" {
+			return nil, fmt.Errorf("tenant %q: %w", client.tenantName, err)
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+// selectTenantMeta returns the client of the selected tenant as meta without connecting
+// it, for plan-time checks that make no API call.
+func selectTenantMeta(meta interface{}, tenant string) (interface{}, error) {
+	client, ok := meta.(*Client)
+	if !ok {
+		return meta, nil
+	}
+	tenantClient, err := client.forTenant(tenant)
+	if err != nil {
+		if len(client.tenants) > 0 {
+			return nil, fmt.Errorf("%w (configured tenants: %s)", err, strings.Join(client.tenantNames(), ", "))
+		}
+		return nil, err
+	}
+	return tenantClient, nil
+}
+
+// withTenants applies withTenant to every resource and data source of the provider.
+func withTenants(p *schema.Provider) {
+	for _, r := range p.ResourcesMap {
+		withTenant(r, false)
+	}
+	for _, r := range p.DataSourcesMap {
+		withTenant(r, true)
+	}
+}
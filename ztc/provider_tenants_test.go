@@ -0,0 +1,186 @@
+package ztc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
+)
+
+func TestClientForTenant(t *testing.T) {
+	beta := &Client{Service: &zscaler.Service{}, tenantName: "beta"}
+	client := &Client{Service: &zscaler.Service{}, tenants: map[string]*Client{"beta": beta}}
+
+	if got, err := client.forTenant(""); err != nil || got != client {
+		t.Fatalf("empty tenant should select the default client, got %v, %v", got, err)
+	}
+	if got, err := client.forTenant("beta"); err != nil || got != beta {
+		t.Fatalf("expected the beta client, got %v, %v", got, err)
+	}
+	if _, err := client.forTenant("gov"); err == nil {
+		t.Fatal("expected an error for an unknown tenant")
+	}
+	if _, err := (&Client{tenants: client.tenants}).forTenant(""); err == nil {
+		t.Fatal("expected an error when no default credentials are configured")
+	}
+}
+
+func TestWithTenantPassesTenantClient(t *testing.T) {
+	beta := &Client{Service: &zscaler.Service{}, tenantName: "beta"}
+	client := &Client{Service: &zscaler.Service{}, tenants: map[string]*Client{"beta": beta}}
+
+	var got interface{}
+	r := withTenant(&schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Optional: true},
+		},
+		ReadContext: func(_ context.Context, _ *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			got = meta
+			return nil
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: func(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				got = meta
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+	}, false)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"tenant": "beta"})
+	if diags := r.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if got != beta {
+		t.Fatalf("expected the beta client to be passed to Read, got %v", got)
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	d.SetId("beta/1234")
+	if _, err := r.Importer.StateContext(context.Background(), d, client); err != nil {
+		t.Fatal(err)
+	}
+	if got != beta || d.Id() != "1234" || d.Get("tenant").(string) != "beta" {
+		t.Fatalf("expected import to select tenant beta and strip the prefix, got id %q tenant %q", d.Id(), d.Get("tenant"))
+	}
+}
+
+func TestClientConnectsOnce(t *testing.T) {
+	var calls int32
+	client := &Client{newService: func() (*zscaler.Service, error) {
+		atomic.AddInt32(&calls, 1)
+		return &zscaler.Service{}, nil
+	}}
+	if client.Service != nil {
+		t.Fatal("the service must not be built before first use")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := tenantMetaFor(client, ""); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if atomic.LoadInt32(&calls) != 1 || client.Service == nil {
+		t.Fatalf("expected the service to be built once, got %d builds", calls)
+	}
+}
+
+func TestClientConnectError(t *testing.T) {
+	var calls int
+	beta := &Client{tenantName: "beta", newService: func() (*zscaler.Service, error) {
+		calls++
+		return nil, errors.New("invalid authentication configuration")
+	}}
+	client := &Client{tenants: map[string]*Client{"beta": beta}}
+
+	for i := 0; i < 2; i++ {
+		_, err := tenantMetaFor(client, "beta")
+		if err == nil || !strings.Contains(err.Error(), `tenant "beta"`) {
+			t.Fatalf("expected the authentication error of tenant beta, got %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single authentication attempt, got %d", calls)
+	}
+}
+
+func TestSelectTenantMetaDoesNotConnect(t *testing.T) {
+	client := &Client{newService: func() (*zscaler.Service, error) {
+		t.Fatal("plan-time checks must not connect")
+		return nil, nil
+	}}
+	if got, err := selectTenantMeta(client, ""); err != nil || got != client {
+		t.Fatalf("expected the default client, got %v, %v", got, err)
+	}
+}
+
+func TestProviderConfigureWithoutCredentials(t *testing.T) {
+	for _, env := range []string{
+		"ZSCALER_CLIENT_ID", "ZSCALER_CLIENT_SECRET", "ZSCALER_PRIVATE_KEY", "ZSCALER_VANITY_DOMAIN",
+		"ZSCALER_CLOUD", "ZTC_USERNAME", "ZTC_PASSWORD", "ZTC_API_KEY", "ZTC_CLOUD",
+	} {
+		t.Setenv(env, "")
+	}
+	d := schema.TestResourceDataRaw(t, ZTCProvider().Schema, map[string]interface{}{})
+	meta, diags := providerConfigure(d, "1.10.0")
+	if diags.HasError() {
+		t.Fatalf("configuring the provider must not need credentials: %v", diags)
+	}
+	client := meta.(*Client)
+	if client.Service != nil {
+		t.Fatal("the service must not be built while configuring the provider")
+	}
+	if _, err := tenantMetaFor(client, ""); err == nil {
+		t.Fatal("expected the missing credentials to be reported on first use")
+	}
+}
+
+func TestProviderConfigureDefersCredentialSources(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	for _, env := range []string{
+		"ZSCALER_CLIENT_ID", "ZSCALER_CLIENT_SECRET", "ZSCALER_PRIVATE_KEY", "ZSCALER_VANITY_DOMAIN",
+		"ZSCALER_CLIENT_SECRET_FILE", "ZSCALER_PRIVATE_KEY_FILE", "ZSCALER_CREDENTIAL_PROCESS",
+	} {
+		t.Setenv(env, "")
+	}
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	auditPath := filepath.Join(dir, "audit.jsonl")
+	d := schema.TestResourceDataRaw(t, ZTCProvider().Schema, map[string]interface{}{
+		"credential_process": "touch " + marker + " && exit 1",
+		"audit_log_file":     auditPath,
+	})
+	meta, diags := providerConfigure(d, "1.10.0")
+	if diags.HasError() {
+		t.Fatalf("configuring the provider must not load credentials: %v", diags)
+	}
+	for _, path := range []string{marker, auditPath} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("%s was touched while configuring the provider", path)
+		}
+	}
+
+	_, err := tenantMetaFor(meta, "")
+	if err == nil || !strings.Contains(err.Error(), "credential_process") {
+		t.Fatalf("expected credential_process to fail on first use, got %v", err)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Fatal("expected credential_process to run on first use")
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/zscaler/terraform-provider-ztc/ztc/common/resourcetype"
@@ -27,6 +29,13 @@ func init() {
 		"ztc": testAccProvider,
 	}
 
+	if vcrMode() != "" {
+		// Route every API call of the acceptance test provider through the VCR recorder
+		testAccProvider.ConfigureContextFunc = func(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return providerConfigureWithTransport(d, testAccProvider.TerraformVersion, testVCR)
+		}
+	}
+
 	testAccProviderFactories = map[string]func() (*schema.Provider, error){
 		"ztc": func() (*schema.Provider, error) {
 			return testAccProvider, nil
@@ -37,6 +46,13 @@ func init() {
 // TestMain overridden main testing function. Package level BeforeAll and AfterAll.
 // It also delineates between acceptance tests and unit tests
 func TestMain(m *testing.M) {
+	if vcrMode() == vcrModePlay {
+		// Nothing goes over the wire in play mode, so neither real credentials nor the
+		// reorder wait between ticks are needed
+		vcrPlayEnvironment()
+		reorderTickInterval = 10 * time.Millisecond
+	}
+
 	// TF_VAR_hostname allows the real hostname to be scripted into the config tests
 	// see examples/okta_resource_set/basic.tf
 	os.Setenv("TF_VAR_hostname", fmt.Sprintf("%s.%s.%s", os.Getenv("ZSCALER_CLIENT_ID"), os.Getenv("ZSCALER_CLIENT_SECRET"), os.Getenv("ZSCALER_CLOUD")))
@@ -45,7 +61,7 @@ func TestMain(m *testing.M) {
 	// resources.
 	// NOTE: Don't run sweepers if we are playing back VCR as nothing should be
 	// going over the wire
-	if os.Getenv("ZTC_VCR_TF_ACC") != "play" {
+	if vcrMode() != vcrModePlay {
 		setupSweeper(resourcetype.TrafficForwardingRule, sweepTestTrafficForwardingRule)
 		// setupSweeper(resourcetype.IPSourceGroup, sweepTestSourceIPGroup)
 		// setupSweeper(resourcetype.IPDestinationGroup, sweepTestDestinationIPGroup)
@@ -68,11 +84,35 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+func TestProviderResourceTimeouts(t *testing.T) {
+	for name, r := range ZTCProvider().ResourcesMap {
+		if r.Timeouts == nil {
+			t.Errorf("%s does not declare timeouts", name)
+			continue
+		}
+		for op, timeout := range map[string]*time.Duration{
+			"create": r.Timeouts.Create,
+			"read":   r.Timeouts.Read,
+			"update": r.Timeouts.Update,
+			"delete": r.Timeouts.Delete,
+		} {
+			if op == "update" && r.UpdateContext == nil {
+				// Resources whose arguments all force replacement have no update
+				continue
+			}
+			if timeout == nil || *timeout <= 0 {
+				t.Errorf("%s has no %s timeout", name, op)
+			}
+		}
+	}
+}
+
 func TestProvider_impl(t *testing.T) {
 	_ = ZTCProvider()
 }
 
 func testAccPreCheck(t *testing.T) func() {
+	vcrStart(t)
 	return func() {
 		err := accPreCheck()
 		if err != nil {
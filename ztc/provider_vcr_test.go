@@ -0,0 +1,358 @@
+package ztc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// VCR modes selected through ZTC_VCR_TF_ACC (or ZTW_VCR_TF_ACC). In "record" mode the
+// acceptance tests talk to the real API and save every interaction to a cassette per
+// test; in "play" mode they run offline against the saved cassettes.
+const (
+	vcrModeRecord = "record"
+	vcrModePlay   = "play"
+)
+
+func vcrMode() string {
+	if v := os.Getenv("ZTC_VCR_TF_ACC"); v != "" {
+		return v
+	}
+	return os.Getenv("ZTW_VCR_TF_ACC")
+}
+
+// vcrCassetteDir returns where cassettes are stored. It defaults to
+// test-fixtures/cassettes and can be overridden with ZTC_VCR_PATH.
+func vcrCassetteDir() string {
+	if v := os.Getenv("ZTC_VCR_PATH"); v != "" {
+		return v
+	}
+	return filepath.Join("test-fixtures", "cassettes")
+}
+
+type vcrCassette struct {
+	Interactions []vcrInteraction `json:"interactions"`
+}
+
+type vcrInteraction struct {
+	Method          string      `json:"method"`
+	URL             string      `json:"url"`
+	RequestBody     string      `json:"request_body,omitempty"`
+	StatusCode      int         `json:"status_code"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	ResponseBody    string      `json:"response_body,omitempty"`
+}
+
+// testVCR is the recorder shared by the acceptance test provider. Acceptance tests run
+// sequentially, so it switches cassettes from one test to the next.
+var testVCR = &vcrRecorder{next: http.DefaultTransport}
+
+type vcrRecorder struct {
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	mode     string
+	path     string
+	cassette *vcrCassette
+	used     []bool
+}
+
+// vcrStart loads (play) or prepares (record) the cassette for the running test. In record
+// mode the cassette is saved when the test finishes. In play mode a missing cassette fails
+// the test, so that a test that was never recorded cannot pass unnoticed.
+func vcrStart(t *testing.T) {
+	mode := vcrMode()
+	if mode == "" {
+		return
+	}
+	if mode != vcrModeRecord && mode != vcrModePlay {
+		t.Fatalf("unsupported VCR mode %q, expected %q or %q", mode, vcrModeRecord, vcrModePlay)
+	}
+
+	path := filepath.Join(vcrCassetteDir(), vcrCassetteName(t.Name())+".json")
+	cassette := &vcrCassette{}
+	if mode == vcrModePlay {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			t.Fatalf("no VCR cassette recorded at %s; record it with `make testacc-record VCR_RUN=%s`", path, t.Name())
+		}
+		if err != nil {
+			t.Fatalf("reading VCR cassette: %v", err)
+		}
+		if err := json.Unmarshal(data, cassette); err != nil {
+			t.Fatalf("decoding VCR cassette %s: %v", path, err)
+		}
+	}
+
+	testVCR.mu.Lock()
+	testVCR.mode = mode
+	testVCR.path = path
+	testVCR.cassette = cassette
+	testVCR.used = make([]bool, len(cassette.Interactions))
+	testVCR.mu.Unlock()
+
+	t.Cleanup(func() {
+		testVCR.mu.Lock()
+		defer testVCR.mu.Unlock()
+		if testVCR.mode == vcrModeRecord && !t.Skipped() {
+			if err := testVCR.save(); err != nil {
+				t.Errorf("saving VCR cassette: %v", err)
+			}
+		}
+		testVCR.mode = ""
+		testVCR.cassette = nil
+	})
+}
+
+func vcrCassetteName(testName string) string {
+	return regexp.MustCompile(`[^A-Za-z0-9_.-]+`).ReplaceAllString(testName, "_")
+}
+
+func (r *vcrRecorder) save() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func (r *vcrRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	mode := r.mode
+	r.mu.Unlock()
+
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+
+	switch mode {
+	case vcrModePlay:
+		return r.play(req, reqBody)
+	case vcrModeRecord:
+		return r.record(req, reqBody)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	return r.next.RoundTrip(req)
+}
+
+func (r *vcrRecorder) record(req *http.Request, reqBody []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(reqBody))
+	// Let the transport negotiate compression so that recorded bodies are plain text
+	out.Header.Del("Accept-Encoding")
+
+	resp, err := r.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	headers := http.Header{}
+	for k, v := range resp.Header {
+		if vcrSensitiveHeader(k) {
+			continue
+		}
+		headers[k] = v
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cassette != nil {
+		r.cassette.Interactions = append(r.cassette.Interactions, vcrInteraction{
+			Method:          req.Method,
+			URL:             vcrScrub(req.URL.String()),
+			RequestBody:     vcrScrub(string(reqBody)),
+			StatusCode:      resp.StatusCode,
+			ResponseHeaders: headers,
+			ResponseBody:    vcrScrub(string(respBody)),
+		})
+		r.used = append(r.used, true)
+	}
+	return resp, nil
+}
+
+// play answers the request from the cassette. Matching ignores the order in which
+// requests are made: the first unused interaction with the same method, URL and body
+// wins, and once all of them are used the last one is replayed again, which covers
+// polling reads.
+func (r *vcrRecorder) play(req *http.Request, reqBody []byte) (*http.Response, error) {
+	key := vcrRequestKey(req.Method, vcrScrub(req.URL.String()), vcrScrub(string(reqBody)))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cassette == nil {
+		return nil, fmt.Errorf("VCR: no cassette loaded for %s %s", req.Method, req.URL.Path)
+	}
+	match := -1
+	for i, in := range r.cassette.Interactions {
+		if vcrRequestKey(in.Method, in.URL, in.RequestBody) != key {
+			continue
+		}
+		if !r.used[i] {
+			match = i
+			break
+		}
+		match = i
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("VCR: no recorded interaction in %s for %s %s", r.path, req.Method, req.URL.Path)
+	}
+	r.used[match] = true
+
+	in := r.cassette.Interactions[match]
+	header := in.ResponseHeaders.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.StatusCode, http.StatusText(in.StatusCode)),
+		StatusCode:    in.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(in.ResponseBody)),
+		ContentLength: int64(len(in.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+// vcrRequestKey normalizes a request so that query parameter order and JSON key order
+// do not affect matching.
+func vcrRequestKey(method, rawURL, body string) string {
+	u, err := url.Parse(rawURL)
+	if err == nil {
+		u.RawQuery = u.Query().Encode()
+		rawURL = u.String()
+	}
+	var v interface{}
+	if body != "" && json.Unmarshal([]byte(body), &v) == nil {
+		if normalized, err := json.Marshal(v); err == nil {
+			body = string(normalized)
+		}
+	}
+	return method + " " + rawURL + " " + body
+}
+
+func vcrSensitiveHeader(name string) bool {
+	switch http.CanonicalHeaderKey(name) {
+	case "Authorization", "Set-Cookie", "Cookie", "X-Auth-Token":
+		return true
+	}
+	return false
+}
+
+var vcrTokenPattern = regexp.MustCompile(`("(?:access_token|refresh_token|id_token|token|jsessionid|JSESSIONID)"\s*:\s*)"[^"]*"`)
+
+// vcrScrub removes credentials, the vanity domain and tokens from recorded text. The
+// same substitutions are applied to live requests in play mode so that they match.
+func vcrScrub(s string) string {
+	secrets := map[string]string{
+		"ZSCALER_CLIENT_ID":     "vcr-client-id",
+		"ZSCALER_CLIENT_SECRET": "vcr-client-secret",
+		"ZSCALER_PRIVATE_KEY":   "vcr-private-key",
+		"ZSCALER_VANITY_DOMAIN": "vcr-vanity-domain",
+		"ZTC_USERNAME":          "vcr-username",
+		"ZTC_PASSWORD":          "vcr-password",
+		"ZTC_API_KEY":           "vcr-api-key",
+	}
+	envs := make([]string, 0, len(secrets))
+	for env := range secrets {
+		envs = append(envs, env)
+	}
+	// Replace longer values first so that a value contained in another is not split
+	sort.Slice(envs, func(i, j int) bool { return len(os.Getenv(envs[i])) > len(os.Getenv(envs[j])) })
+	for _, env := range envs {
+		value := os.Getenv(env)
+		if value == "" || value == secrets[env] {
+			continue
+		}
+		s = strings.ReplaceAll(s, value, secrets[env])
+		if escaped := url.QueryEscape(value); escaped != value {
+			s = strings.ReplaceAll(s, escaped, secrets[env])
+		}
+	}
+	return vcrTokenPattern.ReplaceAllString(s, `$1"REDACTED"`)
+}
+
+// vcrPlayEnvironment provides placeholder credentials in play mode so that the
+// acceptance test pre-checks pass without real secrets.
+func vcrPlayEnvironment() {
+	defaults := map[string]string{
+		"ZSCALER_CLIENT_ID":     "vcr-client-id",
+		"ZSCALER_CLIENT_SECRET": "vcr-client-secret",
+		"ZSCALER_VANITY_DOMAIN": "vcr-vanity-domain",
+	}
+	for env, value := range defaults {
+		if os.Getenv(env) == "" {
+			os.Setenv(env, value)
+		}
+	}
+}
+
+func TestVCRScrubAndMatch(t *testing.T) {
+	t.Setenv("ZSCALER_CLIENT_SECRET", "s3cr3t/+")
+	t.Setenv("ZSCALER_VANITY_DOMAIN", "acme")
+
+	scrubbed := vcrScrub(`client_secret=s3cr3t%2F%2B&host=acme.zslogin.net {"access_token": "eyJabc"}`)
+	for _, leaked := range []string{"s3cr3t", "acme", "eyJabc"} {
+		if strings.Contains(scrubbed, leaked) {
+			t.Fatalf("scrubbed text still contains %q: %s", leaked, scrubbed)
+		}
+	}
+
+	a := vcrRequestKey("GET", "https://api.example/ecRules?b=2&a=1", `{"name":"x","order":1}`)
+	b := vcrRequestKey("GET", "https://api.example/ecRules?a=1&b=2", `{"order":1,"name":"x"}`)
+	if a != b {
+		t.Fatalf("expected equivalent requests to match:\n%s\n%s", a, b)
+	}
+}
+
+func TestVCRPlayIsOrderIndependent(t *testing.T) {
+	r := &vcrRecorder{
+		mode: vcrModePlay,
+		cassette: &vcrCassette{Interactions: []vcrInteraction{
+			{Method: "GET", URL: "https://api.example/a", StatusCode: 200, ResponseBody: "first"},
+			{Method: "GET", URL: "https://api.example/b", StatusCode: 200, ResponseBody: "second"},
+		}},
+		used: make([]bool, 2),
+	}
+	for _, want := range []struct{ path, body string }{{"/b", "second"}, {"/a", "first"}, {"/a", "first"}} {
+		req, _ := http.NewRequest("GET", "https://api.example"+want.path, nil)
+		resp, err := r.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != want.body {
+			t.Fatalf("GET %s: got %q, want %q", want.path, body, want.body)
+		}
+	}
+	req, _ := http.NewRequest("DELETE", "https://api.example/a", nil)
+	if _, err := r.RoundTrip(req); err == nil {
+		t.Fatal("expected an error for an unrecorded request")
+	}
+}
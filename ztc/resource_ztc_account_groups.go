@@ -18,6 +18,7 @@ func resourceAccountGroup() *schema.Resource {
 		ReadContext:   resourceAccountGroupRead,
 		UpdateContext: resourceAccountGroupUpdate,
 		DeleteContext: resourceAccountGroupDelete,
+		Timeouts:      resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
@@ -2,7 +2,10 @@ package ztc
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"sort"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -16,7 +19,17 @@ func resourceActivationStatus() *schema.Resource {
 		CreateContext: resourceActivationStatusCreate,
 		ReadContext:   resourceActivationStatusRead,
 		DeleteContext: resourceFuncNoOp,
-		Importer:      &schema.ResourceImporter{},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+		Importer: &schema.ResourceImporter{},
+		// Version 1 replaces the admin_status_map string map with the admin_status list
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			stateUpgrader(0, resourceActivationStatusV0(), resourceActivationStatusStateUpgradeV0),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"org_edit_status": {
@@ -42,14 +55,7 @@ func resourceActivationStatus() *schema.Resource {
 					"CAC_ACTV_TIMER",
 				}, false),
 			},
-			"admin_status_map": {
-				Type:     schema.TypeMap,
-				Computed: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-				Description: "Admin status",
-			},
+			"admin_status": adminStatusSchema(),
 			"admin_activate_status": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -64,6 +70,29 @@ func resourceActivationStatus() *schema.Resource {
 					"ADM_EXPIRED",
 				}, false),
 			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Reason for the activation, recorded in the change summary",
+			},
+			"change_ticket": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Change management ticket of the activation, recorded in the change summary",
+			},
+			"change_summary_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Path of a local file to which the change summary is written on activation",
+			},
+			"change_summary": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON summary of the resources created, updated and deleted by this run before the activation",
+			},
 		},
 	}
 }
@@ -82,6 +111,19 @@ func resourceActivationStatusCreate(ctx context.Context, d *schema.ResourceData,
 
 	log.Printf("[INFO] Configuration activation successfull. %v\n", resp.AdminActivateStatus)
 	d.SetId("activation")
+
+	if zClient.changes != nil {
+		summary := zClient.changes.drain(time.Now())
+		summary.Comment = d.Get("comment").(string)
+		summary.ChangeTicket = d.Get("change_ticket").(string)
+		summary.Tenant = zClient.tenantName
+		_ = d.Set("change_summary", summary.String())
+		if path := d.Get("change_summary_file").(string); path != "" {
+			if err := writeChangeSummaryFile(path, summary.String()); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
 	return resourceActivationStatusRead(ctx, d, meta)
 }
 
@@ -103,7 +145,7 @@ func resourceActivationStatusRead(ctx context.Context, d *schema.ResourceData, m
 	log.Printf("[INFO] Reading activation status: %+v\n", resp)
 	_ = d.Set("org_edit_status", resp.OrgEditStatus)
 	_ = d.Set("org_last_activate_status", resp.OrgLastActivateStatus)
-	_ = d.Set("admin_status_map", resp.AdminStatusMap)
+	_ = d.Set("admin_status", flattenAdminStatus(resp.AdminStatusMap))
 	_ = d.Set("admin_activate_status", resp.AdminActivateStatus)
 
 	return nil
@@ -118,7 +160,57 @@ func expandActivationStatus(d *schema.ResourceData) activation.ECAdminActivation
 	return activation.ECAdminActivation{
 		OrgEditStatus:         d.Get("org_edit_status").(string),
 		OrgLastActivateStatus: d.Get("org_last_activate_status").(string),
-		AdminStatusMap:        d.Get("admin_status_map").(map[string]interface{}),
+		AdminStatusMap:        expandAdminStatus(d.Get("admin_status").([]interface{})),
 		AdminActivateStatus:   d.Get("admin_activate_status").(string),
 	}
 }
+
+func adminStatusSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Activation status of each admin",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"admin_id": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Admin ID",
+				},
+				"status": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Activation status of the admin",
+				},
+			},
+		},
+	}
+}
+
+// flattenAdminStatus returns the admin status map of the API, keyed by admin ID, as a
+// list sorted by admin ID.
+func flattenAdminStatus(statusMap map[string]interface{}) []interface{} {
+	ids := make([]string, 0, len(statusMap))
+	for id := range statusMap {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	result := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, map[string]interface{}{
+			"admin_id": id,
+			"status":   fmt.Sprint(statusMap[id]),
+		})
+	}
+	return result
+}
+
+func expandAdminStatus(list []interface{}) map[string]interface{} {
+	statusMap := make(map[string]interface{}, len(list))
+	for _, v := range list {
+		if m, ok := v.(map[string]interface{}); ok {
+			statusMap[m["admin_id"].(string)] = m["status"]
+		}
+	}
+	return statusMap
+}
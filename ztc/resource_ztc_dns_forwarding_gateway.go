@@ -19,6 +19,7 @@ func resourceDNSForwardingGateway() *schema.Resource {
 		ReadContext:   resourceDNSForwardingGatewayRead,
 		UpdateContext: resourceDNSForwardingGatewayUpdate,
 		DeleteContext: resourceDNSForwardingGatewayDelete,
+		Timeouts:      resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
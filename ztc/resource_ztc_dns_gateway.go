@@ -19,6 +19,7 @@ func resourceDNSGateway() *schema.Resource {
 		ReadContext:   resourceDNSGatewayRead,
 		UpdateContext: resourceDNSGatewayUpdate,
 		DeleteContext: resourceDNSGatewayDelete,
+		Timeouts:      resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
@@ -20,6 +20,7 @@ func resourceForwardingGateway() *schema.Resource {
 		ReadContext:   resourceForwardingGatewayRead,
 		UpdateContext: resourceForwardingGatewayUpdate,
 		DeleteContext: resourceForwardingGatewayDelete,
+		Timeouts:      resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
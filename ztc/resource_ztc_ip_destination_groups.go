@@ -22,6 +22,7 @@ func resourceIPDestinationGroups() *schema.Resource {
 		ReadContext:   resourceIPDestinationGroupsRead,
 		UpdateContext: resourceIPDestinationGroupsUpdate,
 		DeleteContext: resourceIPDestinationGroupsDelete,
+		Timeouts:      resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
@@ -19,6 +19,7 @@ func resourceIPPoolGroups() *schema.Resource {
 		ReadContext:   resourceIPPoolSourceGroupsRead,
 		UpdateContext: resourceIPPoolSourceGroupsUpdate,
 		DeleteContext: resourceIPPoolSourceGroupsDelete,
+		Timeouts:      resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
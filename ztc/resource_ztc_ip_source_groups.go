@@ -21,6 +21,7 @@ func resourceIPSourceGroups() *schema.Resource {
 		ReadContext:   resourceIPSourceGroupsGroupsRead,
 		UpdateContext: resourceIPSourceGroupsGroupsUpdate,
 		DeleteContext: resourceIPSourceGroupsGroupsDelete,
+		Timeouts:      resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
@@ -20,6 +20,7 @@ func resourceLocationManagement() *schema.Resource {
 		ReadContext:   resourceLocationManagementRead,
 		UpdateContext: resourceLocationManagementUpdate,
 		DeleteContext: resourceLocationManagementDelete,
+		Timeouts: resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
@@ -19,6 +19,7 @@ func resourceLocationTemplate() *schema.Resource {
 		ReadContext:   resourceLocationTemplateRead,
 		UpdateContext: resourceLocationTemplateUpdate,
 		DeleteContext: resourceLocationTemplateDelete,
+		Timeouts:      resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
@@ -61,9 +62,10 @@ func resourceLocationTemplate() *schema.Resource {
 				Optional: true,
 			},
 			"template": {
-				Type:     schema.TypeSet,
+				Type:     schema.TypeList,
 				Computed: true,
 				Optional: true,
+				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"template_prefix": {
@@ -123,14 +125,40 @@ func resourceLocationTemplate() *schema.Resource {
 							Optional:     true,
 							Computed:     true,
 							ValidateFunc: validation.IntBetween(0, 99999999),
-							Description:  "Upload bandwidth in bytes. The value 0 implies no Bandwidth Control enforcement.",
+							Description:  "Upload bandwidth in Kbps. The value 0 implies no Bandwidth Control enforcement.",
 						},
 						"dn_bandwidth": {
 							Type:         schema.TypeInt,
 							Optional:     true,
 							Computed:     true,
 							ValidateFunc: validation.IntBetween(0, 99999999),
-							Description:  "Upload bandwidth in bytes. The value 0 implies no Bandwidth Control enforcement.",
+							Description:  "Download bandwidth in Kbps. The value 0 implies no Bandwidth Control enforcement.",
+						},
+						"idle_time_in_minutes": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Idle Time to Disassociation. Required when surrogate IP is enabled.",
+						},
+						"surrogate_ip": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+							Description: "Enable Surrogate IP. When set to true, users are mapped to internal device IP addresses. Requires auth_required.",
+						},
+						"surrogate_ip_enforced_for_known_browsers": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+							Description: "Enforce Surrogate IP for Known Browsers. Requires surrogate_ip.",
+						},
+						"surrogate_refresh_time_in_minutes": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Refresh Time for re-validation of Surrogacy. Required when surrogate IP is enforced for known browsers.",
 						},
 					},
 				},
@@ -161,18 +189,34 @@ func resourceLocationTemplateCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func checkLocationTemplateDependencies(template locationtemplate.LocationTemplate) error {
-	if template.LocationTemplateDetails.AuthRequired && template.LocationTemplateDetails.CautionEnabled {
+	details := template.LocationTemplateDetails
+	if details == nil {
+		return nil
+	}
+	if details.AuthRequired && details.CautionEnabled {
 		return fmt.Errorf("authentication required must be disabled, when enabling caution")
 	}
-	if template.LocationTemplateDetails.AupEnabled && template.LocationTemplateDetails.CautionEnabled {
+	if details.AupEnabled && details.CautionEnabled {
 		return fmt.Errorf("enabling AUP and Caution together is not allowed")
 	}
-	if template.LocationTemplateDetails.AupEnabled && template.LocationTemplateDetails.AupTimeoutInDays == 0 {
+	if details.AupEnabled && details.AupTimeoutInDays == 0 {
 		return fmt.Errorf("AUP timeout in days is required, when AUP is enabled")
 	}
-	if template.LocationTemplateDetails.EnforceBandwidthControl && template.LocationTemplateDetails.UpBandwidth == 0 && template.LocationTemplateDetails.DnBandwidth == 0 {
+	if details.EnforceBandwidthControl && details.UpBandwidth == 0 && details.DnBandwidth == 0 {
 		return fmt.Errorf("upload and download bandwidth is mandatory when enforce bandwidth setting is on")
 	}
+	if details.SurrogateIP && details.IdleTimeInMinutes == 0 {
+		return fmt.Errorf("surrogate IP requires setting of an idle timeout")
+	}
+	if details.SurrogateIP && !details.AuthRequired {
+		return fmt.Errorf("authentication required must be enabled, when enabling surrogate IP")
+	}
+	if details.SurrogateIPEnforcedForKnownBrowsers && !details.SurrogateIP {
+		return fmt.Errorf("surrogate IP must be enabled, when enforcing surrogate IP for known browsers")
+	}
+	if details.SurrogateIPEnforcedForKnownBrowsers && details.SurrogateRefreshTimeInMinutes == 0 {
+		return fmt.Errorf("enforcing surrogate IP for known browsers requires setting of refresh timeout")
+	}
 	return nil
 }
 
@@ -249,48 +293,38 @@ func resourceLocationTemplateDelete(ctx context.Context, d *schema.ResourceData,
 
 func expandLocationTemplate(d *schema.ResourceData) locationtemplate.LocationTemplate {
 	id, _ := getIntFromResourceData(d, "template_id")
-	result := locationtemplate.LocationTemplate{
-		ID:          id,
-		Name:        d.Get("name").(string),
-		Description: d.Get("desc").(string),
-
+	return locationtemplate.LocationTemplate{
+		ID:                      id,
+		Name:                    d.Get("name").(string),
+		Description:             d.Get("desc").(string),
 		LocationTemplateDetails: expandLocationTemplateDetails(d),
 	}
-	templateDetails := expandLocationTemplateDetails(d)
-	if templateDetails != nil {
-		result.LocationTemplateDetails = templateDetails
-	}
-	return result
 }
 
 func expandLocationTemplateDetails(d *schema.ResourceData) *locationtemplate.LocationTemplateDetails {
-	templateObj, ok := d.GetOk("template")
-	if !ok {
+	templates, ok := d.Get("template").([]interface{})
+	if !ok || len(templates) == 0 {
 		return nil
 	}
-	templates, ok := templateObj.(*schema.Set)
+	template, ok := templates[0].(map[string]interface{})
 	if !ok {
 		return nil
 	}
-	if len(templates.List()) > 0 {
-		templateObj := templates.List()[0]
-		template, ok := templateObj.(map[string]interface{})
-		if !ok {
-			return nil
-		}
-		return &locationtemplate.LocationTemplateDetails{
-			TemplatePrefix:          template["template_prefix"].(string),
-			XFFForwardEnabled:       template["xff_forward_enabled"].(bool),
-			AuthRequired:            template["auth_required"].(bool),
-			CautionEnabled:          template["caution_enabled"].(bool),
-			AupEnabled:              template["aup_enabled"].(bool),
-			AupTimeoutInDays:        template["aup_timeout_in_days"].(int),
-			OFWEnabled:              template["ofw_enabled"].(bool),
-			IPSControl:              template["ips_control"].(bool),
-			EnforceBandwidthControl: template["enforce_bandwidth_control"].(bool),
-			UpBandwidth:             template["up_bandwidth"].(int),
-			DnBandwidth:             template["dn_bandwidth"].(int),
-		}
+	return &locationtemplate.LocationTemplateDetails{
+		TemplatePrefix:                      template["template_prefix"].(string),
+		XFFForwardEnabled:                   template["xff_forward_enabled"].(bool),
+		AuthRequired:                        template["auth_required"].(bool),
+		CautionEnabled:                      template["caution_enabled"].(bool),
+		AupEnabled:                          template["aup_enabled"].(bool),
+		AupTimeoutInDays:                    template["aup_timeout_in_days"].(int),
+		OFWEnabled:                          template["ofw_enabled"].(bool),
+		IPSControl:                          template["ips_control"].(bool),
+		EnforceBandwidthControl:             template["enforce_bandwidth_control"].(bool),
+		UpBandwidth:                         template["up_bandwidth"].(int),
+		DnBandwidth:                         template["dn_bandwidth"].(int),
+		IdleTimeInMinutes:                   template["idle_time_in_minutes"].(int),
+		SurrogateIP:                         template["surrogate_ip"].(bool),
+		SurrogateIPEnforcedForKnownBrowsers: template["surrogate_ip_enforced_for_known_browsers"].(bool),
+		SurrogateRefreshTimeInMinutes:       template["surrogate_refresh_time_in_minutes"].(int),
 	}
-	return nil
 }
@@ -0,0 +1,34 @@
+package ztc
+
+import (
+	"testing"
+
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/locationmanagement/locationtemplate"
+)
+
+func TestCheckLocationTemplateDependencies(t *testing.T) {
+	cases := map[string]struct {
+		details *locationtemplate.LocationTemplateDetails
+		wantErr bool
+	}{
+		"no template":     {nil, false},
+		"plain template":  {&locationtemplate.LocationTemplateDetails{OFWEnabled: true}, false},
+		"caution and aup": {&locationtemplate.LocationTemplateDetails{AupEnabled: true, AupTimeoutInDays: 1, CautionEnabled: true}, true},
+		"surrogate ip": {&locationtemplate.LocationTemplateDetails{
+			AuthRequired: true, SurrogateIP: true, IdleTimeInMinutes: 480,
+			SurrogateIPEnforcedForKnownBrowsers: true, SurrogateRefreshTimeInMinutes: 60,
+		}, false},
+		"surrogate ip without idle time":      {&locationtemplate.LocationTemplateDetails{AuthRequired: true, SurrogateIP: true}, true},
+		"surrogate ip without authentication": {&locationtemplate.LocationTemplateDetails{SurrogateIP: true, IdleTimeInMinutes: 480}, true},
+		"known browsers without surrogate ip": {&locationtemplate.LocationTemplateDetails{SurrogateIPEnforcedForKnownBrowsers: true, SurrogateRefreshTimeInMinutes: 60}, true},
+		"known browsers without refresh time": {&locationtemplate.LocationTemplateDetails{
+			AuthRequired: true, SurrogateIP: true, IdleTimeInMinutes: 480, SurrogateIPEnforcedForKnownBrowsers: true,
+		}, true},
+	}
+	for name, tc := range cases {
+		err := checkLocationTemplateDependencies(locationtemplate.LocationTemplate{LocationTemplateDetails: tc.details})
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: got error %v, want error %v", name, err, tc.wantErr)
+		}
+	}
+}
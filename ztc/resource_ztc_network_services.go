@@ -21,6 +21,7 @@ func resourceNetworkServices() *schema.Resource {
 		ReadContext:   resourceNetworkServicesRead,
 		UpdateContext: resourceNetworkServicesUpdate,
 		DeleteContext: resourceNetworkServicesDelete,
+		Timeouts:      resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
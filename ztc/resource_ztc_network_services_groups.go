@@ -21,6 +21,7 @@ func resourceNetworkServiceGroups() *schema.Resource {
 		ReadContext:   resourceNetworkServiceGroupsRead,
 		UpdateContext: resourceNetworkServiceGroupsUpdate,
 		DeleteContext: resourceNetworkServiceGroupsDelete,
+		Timeouts:      resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
@@ -20,6 +20,7 @@ func resourceProvisioningURL() *schema.Resource {
 		ReadContext:   resourceProvisioningURLRead,
 		UpdateContext: resourceProvisioningURLUpdate,
 		DeleteContext: resourceProvisioningURLDelete,
+		Timeouts:      resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
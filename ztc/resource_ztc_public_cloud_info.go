@@ -19,6 +19,7 @@ func resourcePublicCloudInfo() *schema.Resource {
 		ReadContext:   resourcePublicCloudInfoRead,
 		UpdateContext: resourcePublicCloudInfoUpdate,
 		DeleteContext: resourcePublicCloudInfoDelete,
+		Timeouts:      resourceTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
@@ -0,0 +1,327 @@
+package ztc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/forwarding_rules"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/traffic_dns_rules"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/traffic_log_rules"
+)
+
+// ruleStateTarget is a rule whose state can be overridden.
+type ruleStateTarget struct {
+	ID         int
+	Name       string
+	State      string
+	Predefined bool
+}
+
+// ruleStateService lists the rules of one rule type and changes the state of a single
+// rule without touching its order or rank.
+type ruleStateService struct {
+	list     func(ctx context.Context, service *zscaler.Service) ([]ruleStateTarget, error)
+	setState func(ctx context.Context, service *zscaler.Service, id int, state string) error
+}
+
+var ruleStateServices = map[string]ruleStateService{
+	"forwarding": {
+		list: func(ctx context.Context, service *zscaler.Service) ([]ruleStateTarget, error) {
+			rules, err := forwarding_rules.GetAll(ctx, service)
+			if err != nil {
+				return nil, err
+			}
+			targets := make([]ruleStateTarget, 0, len(rules))
+			for _, r := range rules {
+				targets = append(targets, ruleStateTarget{ID: r.ID, Name: r.Name, State: r.State, Predefined: validatePredefinedRules(r) != nil})
+			}
+			return targets, nil
+		},
+		setState: func(ctx context.Context, service *zscaler.Service, id int, state string) error {
+			rule, err := getRule(ctx, service, id)
+			if err != nil {
+				return err
+			}
+			// to avoid the STALE_CONFIGURATION_ERROR
+			rule.LastModifiedTime = 0
+			rule.LastModifiedBy = nil
+			rule.State = state
+			_, err = forwarding_rules.Update(ctx, service, id, rule)
+			return err
+		},
+	},
+	"dns": {
+		list: func(ctx context.Context, service *zscaler.Service) ([]ruleStateTarget, error) {
+			rules, err := traffic_dns_rules.GetAll(ctx, service)
+			if err != nil {
+				return nil, err
+			}
+			targets := make([]ruleStateTarget, 0, len(rules))
+			for _, r := range rules {
+				targets = append(targets, ruleStateTarget{ID: r.ID, Name: r.Name, State: r.State, Predefined: validatePredefinedDNSRules(r) != nil})
+			}
+			return targets, nil
+		},
+		setState: func(ctx context.Context, service *zscaler.Service, id int, state string) error {
+			rule, err := traffic_dns_rules.Get(ctx, service, id)
+			if err != nil {
+				return err
+			}
+			// to avoid the STALE_CONFIGURATION_ERROR
+			rule.LastModifiedTime = 0
+			rule.LastModifiedBy = nil
+			rule.State = state
+			_, err = traffic_dns_rules.Update(ctx, service, id, rule)
+			return err
+		},
+	},
+	"log": {
+		list: func(ctx context.Context, service *zscaler.Service) ([]ruleStateTarget, error) {
+			rules, err := traffic_log_rules.GetAll(ctx, service)
+			if err != nil {
+				return nil, err
+			}
+			targets := make([]ruleStateTarget, 0, len(rules))
+			for _, r := range rules {
+				targets = append(targets, ruleStateTarget{ID: r.ID, Name: r.Name, State: r.State, Predefined: validatePredefinedLogRules(r) != nil})
+			}
+			return targets, nil
+		},
+		setState: func(ctx context.Context, service *zscaler.Service, id int, state string) error {
+			rule, err := traffic_log_rules.Get(ctx, service, id)
+			if err != nil {
+				return err
+			}
+			// to avoid the STALE_CONFIGURATION_ERROR
+			rule.LastModifiedTime = 0
+			rule.LastModifiedBy = nil
+			rule.State = state
+			_, err = traffic_log_rules.Update(ctx, service, id, rule)
+			return err
+		},
+	},
+}
+
+func resourceRuleStateOverride() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRuleStateOverrideCreate,
+		ReadContext:   resourceRuleStateOverrideRead,
+		UpdateContext: resourceRuleStateOverrideUpdate,
+		DeleteContext: resourceRuleStateOverrideDelete,
+		Timeouts:      resourceTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"rule_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"forwarding", "dns", "log"}, false),
+				Description:  "Type of the rules to override: `forwarding`, `dns` or `log`",
+			},
+			"rule_ids": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ForceNew:     true,
+				Elem:         &schema.Schema{Type: schema.TypeInt},
+				ExactlyOneOf: []string{"rule_ids", "name_pattern"},
+				Description:  "IDs of the rules to override",
+			},
+			"name_pattern": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				ExactlyOneOf: []string{"rule_ids", "name_pattern"},
+				Description:  "Regular expression matched against the rule names. The rules are selected when the override is created",
+			},
+			"state": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"ENABLED", "DISABLED"}, false),
+				Description:  "State applied to the selected rules",
+			},
+			"matched_rule_ids": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "IDs of the rules the override applies to",
+			},
+			"original_states": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "State of each selected rule before the override, by rule ID. Restored on destroy",
+			},
+		},
+	}
+}
+
+// selectRuleStateTargets returns the user defined rules selected by rule_ids or name_pattern.
+func selectRuleStateTargets(rules []ruleStateTarget, ids []int, pattern string) ([]ruleStateTarget, error) {
+	var re *regexp.Regexp
+	if pattern != "" {
+		var err error
+		if re, err = regexp.Compile(pattern); err != nil {
+			return nil, err
+		}
+	}
+	wanted := map[int]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var selected []ruleStateTarget
+	for _, r := range rules {
+		if r.Predefined {
+			if wanted[r.ID] {
+				return nil, fmt.Errorf("rule %d (%s) is predefined and its state cannot be overridden", r.ID, r.Name)
+			}
+			continue
+		}
+		if (re != nil && re.MatchString(r.Name)) || wanted[r.ID] {
+			selected = append(selected, r)
+			delete(wanted, r.ID)
+		}
+	}
+	if len(wanted) > 0 {
+		missing := make([]int, 0, len(wanted))
+		for id := range wanted {
+			missing = append(missing, id)
+		}
+		sort.Ints(missing)
+		return nil, fmt.Errorf("rules %v were not found", missing)
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no rule matches name_pattern %q", pattern)
+	}
+	return selected, nil
+}
+
+func resourceRuleStateOverrideCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+	api := ruleStateServices[d.Get("rule_type").(string)]
+
+	rules, err := api.list(ctx, service)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var ids []int
+	for _, v := range d.Get("rule_ids").(*schema.Set).List() {
+		ids = append(ids, v.(int))
+	}
+	selected, err := selectRuleStateTargets(rules, ids, d.Get("name_pattern").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	state := d.Get("state").(string)
+	matched := make([]int, 0, len(selected))
+	original := map[string]string{}
+	d.SetId(id.UniqueId())
+	for _, r := range selected {
+		log.Printf("[INFO] Setting state of %s rule %d (%s) to %s\n", d.Get("rule_type"), r.ID, r.Name, state)
+		if err := api.setState(ctx, service, r.ID, state); err != nil {
+			// Keep the rules changed so far in state so that destroy restores them
+			_ = d.Set("matched_rule_ids", matched)
+			_ = d.Set("original_states", original)
+			return diag.FromErr(fmt.Errorf("error setting state of rule %d: %v", r.ID, err))
+		}
+		matched = append(matched, r.ID)
+		original[strconv.Itoa(r.ID)] = r.State
+	}
+	_ = d.Set("matched_rule_ids", matched)
+	_ = d.Set("original_states", original)
+
+	return resourceRuleStateOverrideRead(ctx, d, meta)
+}
+
+func resourceRuleStateOverrideRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+	api := ruleStateServices[d.Get("rule_type").(string)]
+
+	rules, err := api.list(ctx, service)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	byID := map[int]ruleStateTarget{}
+	for _, r := range rules {
+		byID[r.ID] = r
+	}
+
+	state := d.Get("state").(string)
+	var matched []int
+	for _, v := range d.Get("matched_rule_ids").(*schema.Set).List() {
+		r, ok := byID[v.(int)]
+		if !ok {
+			log.Printf("[WARN] Rule %d no longer exists, removing it from rule state override %s", v.(int), d.Id())
+			continue
+		}
+		matched = append(matched, r.ID)
+		if r.State != state {
+			// Report the rule changed outside of Terraform so that the override is applied again
+			state = r.State
+		}
+	}
+	if len(matched) == 0 {
+		log.Printf("[WARN] Removing rule state override %s from state because none of its rules exist", d.Id())
+		d.SetId("")
+		return nil
+	}
+	_ = d.Set("matched_rule_ids", matched)
+	_ = d.Set("state", state)
+	return nil
+}
+
+func resourceRuleStateOverrideUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+	api := ruleStateServices[d.Get("rule_type").(string)]
+
+	state := d.Get("state").(string)
+	for _, v := range d.Get("matched_rule_ids").(*schema.Set).List() {
+		log.Printf("[INFO] Setting state of %s rule %d to %s\n", d.Get("rule_type"), v.(int), state)
+		if err := api.setState(ctx, service, v.(int), state); err != nil {
+			return diag.FromErr(fmt.Errorf("error setting state of rule %d: %v", v.(int), err))
+		}
+	}
+	return resourceRuleStateOverrideRead(ctx, d, meta)
+}
+
+func resourceRuleStateOverrideDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+	api := ruleStateServices[d.Get("rule_type").(string)]
+
+	rules, err := api.list(ctx, service)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	exists := map[int]bool{}
+	for _, r := range rules {
+		exists[r.ID] = true
+	}
+
+	for ruleID, original := range d.Get("original_states").(map[string]interface{}) {
+		id, err := strconv.Atoi(ruleID)
+		if err != nil || !exists[id] {
+			continue
+		}
+		log.Printf("[INFO] Restoring state of %s rule %d to %s\n", d.Get("rule_type"), id, original)
+		if err := api.setState(ctx, service, id, original.(string)); err != nil {
+			return diag.FromErr(fmt.Errorf("error restoring state of rule %d: %v", id, err))
+		}
+	}
+	d.SetId("")
+	return nil
+}
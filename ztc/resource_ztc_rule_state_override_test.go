@@ -0,0 +1,35 @@
+package ztc
+
+import "testing"
+
+func TestSelectRuleStateTargets(t *testing.T) {
+	rules := []ruleStateTarget{
+		{ID: 1, Name: "Default Forwarding Rule", State: "ENABLED", Predefined: true},
+		{ID: 10, Name: "branch-direct", State: "ENABLED"},
+		{ID: 11, Name: "branch-zia", State: "DISABLED"},
+		{ID: 12, Name: "datacenter", State: "ENABLED"},
+	}
+
+	selected, err := selectRuleStateTargets(rules, nil, "^branch-|Default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 2 || selected[0].ID != 10 || selected[1].ID != 11 {
+		t.Fatalf("unexpected selection %+v", selected)
+	}
+
+	selected, err = selectRuleStateTargets(rules, []int{12}, "")
+	if err != nil || len(selected) != 1 || selected[0].ID != 12 {
+		t.Fatalf("unexpected selection %+v, %v", selected, err)
+	}
+
+	if _, err := selectRuleStateTargets(rules, []int{1}, ""); err == nil {
+		t.Error("expected an error for a predefined rule")
+	}
+	if _, err := selectRuleStateTargets(rules, []int{10, 99}, ""); err == nil {
+		t.Error("expected an error for a missing rule")
+	}
+	if _, err := selectRuleStateTargets(rules, nil, "^nomatch$"); err == nil {
+		t.Error("expected an error when nothing matches")
+	}
+}
@@ -8,31 +8,46 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/errorx"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/traffic_dns_rules"
 )
 
-var (
-	trafficForwardingDNSLock          sync.Mutex
-	trafficForwardingDNSStartingOrder int
-)
-
 func resourceTrafficForwardingDNSRule() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceTrafficForwardingDNSRuleCreate,
-		ReadContext:   resourceTrafficForwardingDNSRuleRead,
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return readRuleWithOrderDrift(ctx, d, meta, "ztc_traffic_forwarding_dns_rule", resourceTrafficForwardingDNSRuleRead)
+		},
 		UpdateContext: resourceTrafficForwardingDNSRuleUpdate,
 		DeleteContext: resourceTrafficForwardingDNSRuleDelete,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			// dns_gateway and zpa_ip_group are computed, so only the configuration tells
+			// whether they were set
+			raw := d.GetRawConfig()
+			gateways, gatewaysKnown := rawConfigLength(raw, "dns_gateway")
+			zpaGroups, zpaGroupsKnown := rawConfigLength(raw, "zpa_ip_group")
+			if gatewaysKnown && zpaGroupsKnown {
+				if err := validateDNSRuleAction(d.Get("action").(string), gateways > 0, zpaGroups > 0); err != nil {
+					return err
+				}
+			}
+			if err := validateRuleIDsLimit("locations", d.Get("locations"), dnsRuleMaxLocations); err != nil {
+				return err
+			}
+			return validateRuleIDsLimit("ec_groups", d.Get("ec_groups"), dnsRuleMaxECGroups)
+		},
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(60 * time.Minute),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
 			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -56,7 +71,7 @@ func resourceTrafficForwardingDNSRule() *schema.Resource {
 			},
 		},
 
-		Schema: map[string]*schema.Schema{
+		Schema: MergeSchema(map[string]*schema.Schema{
 			"id": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -67,6 +82,11 @@ func resourceTrafficForwardingDNSRule() *schema.Resource {
 				Computed:    true,
 				Description: "A unique identifier assigned to the forwarding rule",
 			},
+			"predefined": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Indicates that this is a predefined rule created by Zscaler. Predefined rules can be imported and updated, but keep the position assigned by the API and are only removed from state on destroy",
+			},
 			"name": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -82,7 +102,7 @@ func resourceTrafficForwardingDNSRule() *schema.Resource {
 			"order": {
 				Type:        schema.TypeInt,
 				Required:    true,
-				Description: "The order of execution for the forwarding rule order",
+				Description: "The order of execution of the rule among the user-defined DNS forwarding rules. Predefined rules are not counted",
 			},
 			"rank": {
 				Type:        schema.TypeInt,
@@ -121,47 +141,144 @@ func resourceTrafficForwardingDNSRule() *schema.Resource {
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Description: "List of destination IP addresses or FQDNs for which the rule is applicable. CIDR notation can be used for destination IP addresses. If not set, the rule is not restricted to a specific destination addresses unless specified by destCountries, destIpGroups, or destIpCategories.",
 			},
-			"locations":       setIDsSchemaTypeCustom(intPtr(8), "Name-ID pairs of the locations to which the forwarding rule applies. If not set, the rule is applied to all locations."),
+			"locations":       setIDsSchemaTypeCustom(nil, "Name-ID pairs of the locations to which the forwarding rule applies. If not set, the rule is applied to all locations. At most 8 locations can be set."),
 			"location_groups": setIDsSchemaTypeCustom(intPtr(32), "Name-ID pairs of the location groups to which the forwarding rule applies"),
-			"ec_groups":       setIDsSchemaTypeCustom(intPtr(32), "Name-ID pairs of the Zscaler Cloud Connector groups to which the forwarding rule applies"),
+			"ec_groups":       setIDsSchemaTypeCustom(nil, "Name-ID pairs of the Zscaler Cloud Connector groups to which the forwarding rule applies. At most 32 groups can be set."),
 			"src_ip_groups":   setIDsSchemaTypeCustom(nil, "Source IP address groups for which the rule is applicable. If not set, the rule is not restricted to a specific source IP address group"),
 			"dest_ip_groups":  setIDsSchemaTypeCustom(nil, "User-defined destination IP address groups to which the rule is applied. If not set, the rule is not restricted to a specific destination IP address group"),
-			"dns_gateway":     setIdNameSchemaCustom(1, "The dns gateway for which the rule is applicable. This field is applicable only for the Proxy Chaining forwarding method."),
-			"zpa_ip_group":    setIdNameSchemaCustom(1, "The zpa ip group for which the rule is applicable. This field is applicable only for action REDIR_ZPA"),
-		},
+			"dns_gateway":     setIdNameSchemaCustom(1, "The dns gateway for which the rule is applicable. This field is required for action REDIR_REQ and applicable only for it."),
+			"zpa_ip_group":    setIdNameSchemaCustom(1, "The zpa ip group for which the rule is applicable. This field is required for action REDIR_ZPA and applicable only for it."),
+		}, ruleOrderDriftSchema()),
+	}
+}
+
+const (
+	dnsRuleMaxLocations = 8
+	dnsRuleMaxECGroups  = 32
+)
+
+// validateDNSRuleAction checks that the DNS gateway and ZPA IP group match the rule action:
+// REDIR_REQ sends the request to a DNS gateway, REDIR_ZPA resolves through a ZPA IP pool,
+// and ALLOW and BLOCK use neither.
+func validateDNSRuleAction(action string, hasDNSGateway, hasZPAIPGroup bool) error {
+	switch action {
+	case "REDIR_REQ":
+		if !hasDNSGateway {
+			return fmt.Errorf("dns_gateway is required when action is 'REDIR_REQ'")
+		}
+		if hasZPAIPGroup {
+			return fmt.Errorf("zpa_ip_group cannot be set when action is 'REDIR_REQ'")
+		}
+	case "REDIR_ZPA":
+		if !hasZPAIPGroup {
+			return fmt.Errorf("zpa_ip_group is required when action is 'REDIR_ZPA'")
+		}
+		if hasDNSGateway {
+			return fmt.Errorf("dns_gateway cannot be set when action is 'REDIR_ZPA'")
+		}
+	case "ALLOW", "BLOCK":
+		if hasDNSGateway {
+			return fmt.Errorf("dns_gateway cannot be set when action is '%s'; it is only used with 'REDIR_REQ'", action)
+		}
+		if hasZPAIPGroup {
+			return fmt.Errorf("zpa_ip_group cannot be set when action is '%s'; it is only used with 'REDIR_ZPA'", action)
+		}
+	}
+	return nil
+}
+
+// validateRuleIDsLimit checks the number of IDs configured in an attribute built with
+// setIDsSchemaTypeCustom.
+func validateRuleIDsLimit(attr string, value interface{}, max int) error {
+	set, ok := value.(*schema.Set)
+	if !ok {
+		return nil
 	}
+	count := 0
+	for _, item := range set.List() {
+		if m, ok := item.(map[string]interface{}); ok {
+			if ids, ok := m["id"].(*schema.Set); ok {
+				count += ids.Len()
+			}
+		}
+	}
+	if count > max {
+		return fmt.Errorf("%s supports at most %d entries per rule, got %d", attr, max, count)
+	}
+	return nil
 }
 
 func validatePredefinedDNSRules(req traffic_dns_rules.ECDNSRules) error {
-	if req.Name == "ZPA Resolver" || req.Name == "Redirect Resolution of Zscaler Domains to WAN CTR" {
+	if isPredefinedRuleName("traffic_forwarding_dns_rule", req.Name) {
 		return fmt.Errorf("predefined rule '%s' cannot be deleted", req.Name)
 	}
 	return nil
 }
 
+// dnsRuleSlots returns the position of every DNS forwarding rule in the API order.
+func dnsRuleSlots(list []traffic_dns_rules.ECDNSRules) []ruleSlot {
+	slots := make([]ruleSlot, 0, len(list))
+	for _, r := range list {
+		slots = append(slots, ruleSlot{ID: r.ID, Order: r.Order, Predefined: validatePredefinedDNSRules(r) != nil})
+	}
+	return slots
+}
+
+// dnsRuleSlotLister returns the function that lists the slots of the live DNS forwarding rules.
+func dnsRuleSlotLister(service *zscaler.Service) func(ctx context.Context) ([]ruleSlot, error) {
+	return func(ctx context.Context) ([]ruleSlot, error) {
+		list, err := traffic_dns_rules.GetAll(ctx, service)
+		if err != nil {
+			return nil, err
+		}
+		return dnsRuleSlots(list), nil
+	}
+}
+
+// dnsRuleReorder returns the functions with which DNS forwarding rules are moved to their user
+// order, skipping the orders held by predefined rules.
+func dnsRuleReorder(rules *listrules, service *zscaler.Service) (func(ctx context.Context) (int, error), func(ctx context.Context, id int, order OrderRule) error) {
+	return userOrderReorder(rules, "traffic_forwarding_dns_rule", dnsRuleSlotLister(service),
+		func(ctx context.Context, id int, order OrderRule) error {
+			rule, err := traffic_dns_rules.Get(ctx, service, id)
+			if err != nil {
+				return err
+			}
+
+			// to avoid the STALE_CONFIGURATION_ERROR
+			rule.LastModifiedTime = 0
+			rule.LastModifiedBy = nil
+			rule.Order = order.Order
+			rule.Rank = order.Rank
+			_, err = traffic_dns_rules.Update(ctx, service, id, rule)
+			return err
+		},
+	)
+}
+
 func resourceTrafficForwardingDNSRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	zClient := meta.(*Client)
 	service := zClient.Service
 
 	req := expandForwardingDNSRule(d)
+	if err := reservedRuleNameError("traffic_forwarding_dns_rule", req.Name); err != nil {
+		return diag.FromErr(err)
+	}
 	log.Printf("[INFO] Creating ztc traffic dns forwarding rule\n%+v\n", req)
 
 	start := time.Now()
 
 	for {
-		trafficForwardingDNSLock.Lock()
-		if trafficForwardingDNSStartingOrder == 0 {
+		startingOrder := zClient.rules.startingOrder("traffic_forwarding_dns_rule", func() int {
+			highest := 0
 			list, _ := traffic_dns_rules.GetAll(ctx, service)
 			for _, r := range list {
-				if r.Order > trafficForwardingDNSStartingOrder {
-					trafficForwardingDNSStartingOrder = r.Order
+				if r.Order > highest {
+					highest = r.Order
 				}
 			}
-			if trafficForwardingDNSStartingOrder == 0 {
-				trafficForwardingDNSStartingOrder = 1
-			}
-		}
-		trafficForwardingDNSLock.Unlock()
+			return highest
+		})
 		startWithoutLocking := time.Now()
 
 		intendedOrder := req.Order
@@ -170,7 +287,7 @@ func resourceTrafficForwardingDNSRuleCreate(ctx context.Context, d *schema.Resou
 			// always start rank 7 rules at the next available order after all ranked rules
 			req.Rank = 7
 		}
-		req.Order = trafficForwardingDNSStartingOrder
+		req.Order = startingOrder
 		resp, err := traffic_dns_rules.Create(ctx, service, &req)
 
 		// Fail immediately if INVALID_INPUT_ARGUMENT is detected
@@ -191,43 +308,29 @@ func resourceTrafficForwardingDNSRuleCreate(ctx context.Context, d *schema.Resou
 		log.Printf("[INFO] Created ztc traffic dns forwarding rule request. Took: %s, without locking: %s, ID: %v\n", time.Since(start), time.Since(startWithoutLocking), resp)
 		// Use separate resource type for rank 7 rules to avoid mixing with ranked rules
 		resourceType := "traffic_forwarding_dns_rule"
+		zClient.rules.invalidateRuleSlots("traffic_forwarding_dns_rule")
+		getCount, updateOrder := dnsRuleReorder(zClient.rules, service)
 
-		reorderWithBeforeReorder(
+		zClient.rules.reorderWithBeforeReorder(
+			ctx,
 			OrderRule{Order: intendedOrder, Rank: intendedRank},
 			resp.ID,
 			resourceType,
-			func() (int, error) {
-				allRules, err := traffic_dns_rules.GetAll(ctx, service)
-				if err != nil {
-					return 0, err
-				}
-				// Count all rules including predefined ones for proper ordering
-				return len(allRules), nil
-			},
-			func(id int, order OrderRule) error {
-				rule, err := traffic_dns_rules.Get(ctx, service, id)
-				if err != nil {
-					return err
-				}
-
-				// to avoid the STALE_CONFIGURATION_ERROR
-				rule.LastModifiedTime = 0
-				rule.LastModifiedBy = nil
-				rule.Order = order.Order
-				rule.Rank = order.Rank
-				_, err = traffic_dns_rules.Update(ctx, service, id, rule)
-				return err
-			},
+			getCount,
+			updateOrder,
 			nil, // Remove beforeReorder function to avoid adding too many rules to the map
 		)
 
 		d.SetId(strconv.Itoa(resp.ID))
 		_ = d.Set("rule_id", resp.ID)
+		recordIntendedRuleOrder(d)
 
-		markOrderRuleAsDone(resp.ID, resourceType)
-		waitForReorder(resourceType)
+		zClient.rules.markOrderRuleAsDone(resp.ID, resourceType)
+		if err := zClient.rules.waitForReorder(ctx, resourceType, resp.ID); err != nil {
+			return diag.FromErr(fmt.Errorf("error reordering traffic dns forwarding rule %d: %w", resp.ID, err))
+		}
 
-		return resourceTrafficForwardingDNSRuleRead(ctx, d, meta)
+		return readRuleWithOrderDrift(ctx, d, meta, "ztc_traffic_forwarding_dns_rule", resourceTrafficForwardingDNSRuleRead)
 	}
 }
 
@@ -255,9 +358,21 @@ func resourceTrafficForwardingDNSRuleRead(ctx context.Context, d *schema.Resourc
 	d.SetId(fmt.Sprintf("%d", resp.ID))
 	_ = d.Set("name", resp.Name)
 	_ = d.Set("description", resp.Description)
-	_ = d.Set("order", resp.Order)
+	predefined := validatePredefinedDNSRules(*resp) != nil
+	order := resp.Order
+	if !predefined {
+		// The slots are listed once and shared by the reads of all the rules of a refresh
+		slots, err := zClient.rules.ruleSlots(ctx, "traffic_forwarding_dns_rule", dnsRuleSlotLister(service))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		order = newRuleOrderSlots(slots).userOrder(resp.Order)
+	}
+	_ = d.Set("order", order)
 	_ = d.Set("rank", resp.Rank)
+	setRuleOrderDrift(d, order, resp.Rank)
 	_ = d.Set("state", resp.State)
+	_ = d.Set("predefined", predefined)
 	// _ = d.Set("type", resp.Type)
 	_ = d.Set("action", resp.Action)
 	_ = d.Set("src_ips", resp.SrcIps)
@@ -287,6 +402,10 @@ func resourceTrafficForwardingDNSRuleRead(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
+	if err := d.Set("zpa_ip_group", flattenIDNameSet(resp.ZPAIPGroup)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
@@ -301,6 +420,11 @@ func resourceTrafficForwardingDNSRuleUpdate(ctx context.Context, d *schema.Resou
 	}
 	log.Printf("[INFO] Updating traffic dns forwarding rule ID: %v\n", id)
 	req := expandForwardingDNSRule(d)
+	if d.HasChange("name") {
+		if err := reservedRuleNameError("traffic_forwarding_dns_rule", req.Name); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
 	// if _, err := traffic_dns_rules.Get(ctx, service, id); err != nil {
 	// 	if respErr, ok := err.(*errorx.ErrorResponse); ok && respErr.IsObjectNotFound() {
@@ -312,9 +436,31 @@ func resourceTrafficForwardingDNSRuleUpdate(ctx context.Context, d *schema.Resou
 	if err != nil {
 		log.Printf("[ERROR] error getting all traffic dns forwarding rules: %v", err)
 	}
+	for _, r := range existingRules {
+		if r.ID == id && validatePredefinedDNSRules(r) != nil {
+			// Predefined rules keep the position assigned by the API and are never reordered
+			req.Order = r.Order
+			req.Rank = r.Rank
+			if _, err := traffic_dns_rules.Update(ctx, service, id, &req); err != nil {
+				return diag.FromErr(fmt.Errorf("error updating predefined traffic dns forwarding rule %d: %v", id, err))
+			}
+			return resourceTrafficForwardingDNSRuleRead(ctx, d, meta)
+		}
+		if r.ID == id && isRuleMetadataOnlyUpdate(d) {
+			// Only the state or metadata changed: keep the current position and skip the reorder
+			log.Printf("[INFO] Updating traffic dns forwarding rule %d in place at order %d, rank %d\n", id, r.Order, r.Rank)
+			req.Order = r.Order
+			req.Rank = r.Rank
+			if _, err := traffic_dns_rules.Update(ctx, service, id, &req); err != nil {
+				return diag.FromErr(fmt.Errorf("error updating traffic dns forwarding rule %d: %v", id, err))
+			}
+			return readRuleWithOrderDrift(ctx, d, meta, "ztc_traffic_forwarding_dns_rule", resourceTrafficForwardingDNSRuleRead)
+		}
+	}
 	sort.Slice(existingRules, func(i, j int) bool {
 		return existingRules[i].Rank < existingRules[j].Rank || (existingRules[i].Rank == existingRules[j].Rank && existingRules[i].Order < existingRules[j].Order)
 	})
+	recordIntendedRuleOrder(d)
 	intendedOrder := req.Order
 	intendedRank := req.Rank
 	nextAvailableOrder := existingRules[len(existingRules)-1].Order
@@ -334,36 +480,20 @@ func resourceTrafficForwardingDNSRuleUpdate(ctx context.Context, d *schema.Resou
 		return diag.FromErr(fmt.Errorf("error updating resource: %s", err))
 	}
 
-	reorderWithBeforeReorder(OrderRule{Order: intendedOrder, Rank: intendedRank}, req.ID, "traffic_forwarding_dns_rule",
-		func() (int, error) {
-			allRules, err := traffic_dns_rules.GetAll(ctx, service)
-			if err != nil {
-				return 0, err
-			}
-			// Count all rules including predefined ones for proper ordering
-			return len(allRules), nil
-		},
-		func(id int, order OrderRule) error {
-			rule, err := traffic_dns_rules.Get(ctx, service, id)
-			if err != nil {
-				return err
-			}
-
-			// to avoid the STALE_CONFIGURATION_ERROR
-			rule.LastModifiedTime = 0
-			rule.LastModifiedBy = nil
-			rule.Order = order.Order
-			rule.Rank = order.Rank
-			_, err = traffic_dns_rules.Update(ctx, service, id, rule)
-			return err
-		},
+	zClient.rules.invalidateRuleSlots("traffic_forwarding_dns_rule")
+	getCount, updateOrder := dnsRuleReorder(zClient.rules, service)
+	zClient.rules.reorderWithBeforeReorder(ctx, OrderRule{Order: intendedOrder, Rank: intendedRank}, req.ID, "traffic_forwarding_dns_rule",
+		getCount,
+		updateOrder,
 		nil, // Remove beforeReorder function to avoid adding too many rules to the map
 	)
 
-	markOrderRuleAsDone(req.ID, "traffic_forwarding_dns_rule")
-	waitForReorder("traffic_forwarding_dns_rule")
+	zClient.rules.markOrderRuleAsDone(req.ID, "traffic_forwarding_dns_rule")
+	if err := zClient.rules.waitForReorder(ctx, "traffic_forwarding_dns_rule", req.ID); err != nil {
+		return diag.FromErr(fmt.Errorf("error reordering traffic dns forwarding rule %d: %w", req.ID, err))
+	}
 
-	return resourceTrafficForwardingDNSRuleRead(ctx, d, meta)
+	return readRuleWithOrderDrift(ctx, d, meta, "ztc_traffic_forwarding_dns_rule", resourceTrafficForwardingDNSRuleRead)
 }
 
 func resourceTrafficForwardingDNSRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -375,21 +505,23 @@ func resourceTrafficForwardingDNSRuleDelete(ctx context.Context, d *schema.Resou
 		return diag.FromErr(fmt.Errorf("traffic dns forwarding rule ID not set: %v", id))
 	}
 
-	// Retrieve the rule to check if it's a predefined one
-	// rule, err := traffic_dns_rules.Get(ctx, service, id)
-	// if err != nil {
-	// 	return diag.FromErr(fmt.Errorf("error retrieving traffic dns forwarding rule %d: %v", id, err))
-	// }
-
-	// // Validate if the rule can be deleted
-	// if err := validatePredefinedDNSRules(*rule); err != nil {
-	// 	return diag.FromErr(err)
-	// }
+	rule, err := traffic_dns_rules.Get(ctx, service, id)
+	if isRuleNotFound(err) {
+		log.Printf("[WARN] Traffic dns forwarding rule %d no longer exists", id)
+		d.SetId("")
+		return nil
+	}
+	// Predefined rules cannot be deleted; drop them from state with a warning instead
+	if err := predefinedRuleDeleteCheck(d, err, func() error { return validatePredefinedDNSRules(*rule) }); err != nil {
+		d.SetId("")
+		return predefinedRuleDeleteDiagnostics("ztc_traffic_forwarding_dns_rule", id, err)
+	}
 
 	log.Printf("[INFO] Deleting traffic dns forwarding rule ID: %v", id)
 	if _, err := traffic_dns_rules.Delete(ctx, service, id); err != nil {
 		return diag.FromErr(fmt.Errorf("error deleting traffic dns forwarding rule %d: %v", id, err))
 	}
+	zClient.rules.invalidateRuleSlots("traffic_forwarding_dns_rule")
 
 	d.SetId("")
 	log.Printf("[INFO] Traffic dns forwarding rule deleted")
@@ -434,6 +566,7 @@ func expandForwardingDNSRule(d *schema.ResourceData) traffic_dns_rules.ECDNSRule
 		DestIpGroups:    expandIDNameExtensionsSet(d, "dest_ip_groups"),
 		ECGroups:        expandIDNameExtensionsSet(d, "ec_groups"),
 		DNSGateway:      expandIDNameSet(d, "dns_gateway"),
+		ZPAIPGroup:      expandIDNameSet(d, "zpa_ip_group"),
 	}
 	return result
 }
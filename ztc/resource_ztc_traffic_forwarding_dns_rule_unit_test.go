@@ -0,0 +1,61 @@
+package ztc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestValidateDNSRuleAction(t *testing.T) {
+	cases := []struct {
+		action     string
+		gateway    bool
+		zpaIPGroup bool
+		wantErr    string
+	}{
+		{action: "REDIR_REQ", gateway: true},
+		{action: "REDIR_REQ", wantErr: "dns_gateway is required"},
+		{action: "REDIR_REQ", gateway: true, zpaIPGroup: true, wantErr: "zpa_ip_group cannot be set"},
+		{action: "REDIR_ZPA", zpaIPGroup: true},
+		{action: "REDIR_ZPA", wantErr: "zpa_ip_group is required"},
+		{action: "REDIR_ZPA", gateway: true, zpaIPGroup: true, wantErr: "dns_gateway cannot be set"},
+		{action: "ALLOW"},
+		{action: "BLOCK", gateway: true, wantErr: "dns_gateway cannot be set when action is 'BLOCK'"},
+		{action: "ALLOW", zpaIPGroup: true, wantErr: "zpa_ip_group cannot be set when action is 'ALLOW'"},
+		{action: ""},
+	}
+	for _, c := range cases {
+		err := validateDNSRuleAction(c.action, c.gateway, c.zpaIPGroup)
+		if c.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s (gateway=%v, zpa_ip_group=%v): unexpected error %v", c.action, c.gateway, c.zpaIPGroup, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+			t.Errorf("%s (gateway=%v, zpa_ip_group=%v): got %v, want %q", c.action, c.gateway, c.zpaIPGroup, err, c.wantErr)
+		}
+	}
+}
+
+func TestValidateRuleIDsLimit(t *testing.T) {
+	r := resourceTrafficForwardingDNSRule()
+	ids := make([]interface{}, 9)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":      "dns rule",
+		"locations": []interface{}{map[string]interface{}{"id": ids}},
+		"ec_groups": []interface{}{map[string]interface{}{"id": ids}},
+	})
+
+	err := validateRuleIDsLimit("locations", d.Get("locations"), dnsRuleMaxLocations)
+	if err == nil || err.Error() != "locations supports at most 8 entries per rule, got 9" {
+		t.Errorf("unexpected locations error: %v", err)
+	}
+	if err := validateRuleIDsLimit("ec_groups", d.Get("ec_groups"), dnsRuleMaxECGroups); err != nil {
+		t.Errorf("unexpected ec_groups error: %v", err)
+	}
+}
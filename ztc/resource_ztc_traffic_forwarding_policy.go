@@ -0,0 +1,618 @@
+package ztc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/activation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/forwarding_rules"
+)
+
+const forwardingPolicyID = "traffic_forwarding_policy"
+
+func resourceTrafficForwardingPolicy() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceTrafficForwardingPolicyCreate,
+		ReadContext:   resourceTrafficForwardingPolicyRead,
+		UpdateContext: resourceTrafficForwardingPolicyUpdate,
+		DeleteContext: resourceTrafficForwardingPolicyDelete,
+		CustomizeDiff: resourceTrafficForwardingPolicyCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				d.SetId(forwardingPolicyID)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"activate": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Activate the configuration once after all rule changes have been applied",
+			},
+			"rules_to_delete": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Existing forwarding rules that are not in the rule list and are deleted on apply. The apply fails if it would delete any other rule",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"rule_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"rule": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Ordered list of forwarding rules. The position of each block defines the rule order; predefined rules are not part of this list",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"rule_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "A unique identifier assigned to the forwarding rule",
+						},
+						"order": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The order of execution of the forwarding rule among the user-defined rules, derived from its position in the list",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the forwarding rule. Rules are matched to existing rules by rule_id, or by name for new blocks",
+						},
+						"description": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Description:      "Additional information about the forwarding rule",
+							StateFunc:        normalizeMultiLineString,
+							DiffSuppressFunc: noChangeInMultiLineText,
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "EC_RDR",
+							Description: "The rule type selected from the available options",
+							ValidateFunc: validation.StringInSlice([]string{
+								"EC_RDR",
+							}, false),
+						},
+						"forward_method": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The type of traffic forwarding method selected from the available options",
+							ValidateFunc: validation.StringInSlice([]string{
+								"DIRECT",
+								"LOCAL_SWITCH",
+								"ZIA",
+								"ECZPA",
+								"DROP",
+							}, false),
+						},
+						"rank": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      7,
+							ValidateFunc: validation.IntBetween(0, 7),
+							Description:  "Admin rank assigned to the forwarding rule. Ranks must not decrease along the list",
+						},
+						"state": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "ENABLED",
+							Description: "Determines whether the forwarding rule is enabled or disabled",
+							ValidateFunc: validation.StringInSlice([]string{
+								"ENABLED",
+								"DISABLED",
+							}, false),
+						},
+						"src_ips": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "User-defined source IP addresses for which the rule is applicable",
+						},
+						"source_ip_group_exclusion": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Source IP groups that must be excluded from the rule application",
+						},
+						"dest_addresses": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "List of destination IP addresses or FQDNs for which the rule is applicable",
+						},
+						"dest_ip_categories": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "List of destination IP categories to which the rule applies",
+						},
+						"res_categories": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "List of destination domain categories to which the rule applies",
+						},
+						"wan_selection": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "WAN selection is only applicable when configuring a hardware device deployed in gateway mode.",
+							ValidateFunc: validation.StringInSlice([]string{
+								"SMRULEF_ZPA_BROKERS_RULE",
+								"SMRULEF_APPC_DYNAMIC_SRC_IPGROUP",
+								"SMRULEF_EXCL_SRC_IP",
+								"BALANCED_RULE",
+								"BESTLINK_RULE",
+							}, false),
+						},
+						"locations":                      setIDsSchemaTypeCustom(intPtr(8), "Name-ID pairs of the locations to which the forwarding rule applies"),
+						"location_groups":                setIDsSchemaTypeCustom(intPtr(32), "Name-ID pairs of the location groups to which the forwarding rule applies"),
+						"ec_groups":                      setIDsSchemaTypeCustom(intPtr(32), "Name-ID pairs of the Zscaler Cloud Connector groups to which the forwarding rule applies"),
+						"src_ip_groups":                  setIDsSchemaTypeCustom(nil, "Source IP address groups for which the rule is applicable"),
+						"dest_ip_groups":                 setIDsSchemaTypeCustom(nil, "User-defined destination IP address groups to which the rule is applied"),
+						"nw_services":                    setIDsSchemaTypeCustom(intPtr(1024), "User-defined network services to which the rule applies"),
+						"nw_service_groups":              setIDsSchemaTypeCustom(nil, "User-defined network service group to which the rule applies"),
+						"app_service_groups":             setIDsSchemaTypeCustom(nil, "list of application service groups"),
+						"proxy_gateway":                  setIdNameSchemaCustom(1, "The proxy gateway for which the rule is applicable"),
+						"zpa_application_segments":       setIDsSchemaTypeCustom(intPtr(255), "List of ZPA Application Segments for which this rule is applicable. Only for the ECZPA forwarding method"),
+						"zpa_application_segment_groups": setIDsSchemaTypeCustom(intPtr(255), "List of ZPA Application Segment Groups for which this rule is applicable. Only for the ECZPA forwarding method"),
+						"dest_countries":                 getISOCountryCodes(),
+						"src_workload_groups":            setIDsSchemaTypeCustom(nil, "The list of preconfigured workload groups to which the policy must be applied"),
+					},
+				},
+			},
+		},
+	}
+}
+
+// validateForwardingPolicyRules checks the rule blocks at plan time: names must be unique,
+// ranks must not decrease along the list and forward_method specific restrictions apply.
+func validateForwardingPolicyRules(rules []interface{}) error {
+	names := map[string]int{}
+	previousRank := 0
+	for i, raw := range rules {
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := m["name"].(string)
+		if name == "" {
+			// unknown during plan
+			continue
+		}
+		if isPredefinedRuleName("forwarding_control_rule", name) {
+			return fmt.Errorf("rule %d: %q is a predefined rule and cannot be managed by ztc_traffic_forwarding_policy", i, name)
+		}
+		if j, ok := names[name]; ok {
+			return fmt.Errorf("rule %d: name %q is already used by rule %d; rule names must be unique", i, name, j)
+		}
+		names[name] = i
+
+		rank, _ := m["rank"].(int)
+		if rank < previousRank {
+			return fmt.Errorf("rule %d (%q): rank %d cannot be placed after a rule with rank %d; order rules by ascending rank", i, name, rank, previousRank)
+		}
+		previousRank = rank
+
+		forwardMethod, _ := m["forward_method"].(string)
+		for _, attr := range forwardMethodProhibitedAttributes(forwardMethod) {
+			if isNestedAttrSet(m[attr]) {
+				return fmt.Errorf("rule %d (%q): %s attribute cannot be set when forward_method is '%s'", i, name, attr, forwardMethod)
+			}
+		}
+	}
+	return nil
+}
+
+func isNestedAttrSet(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case *schema.Set:
+		return val.Len() > 0
+	case string:
+		return val != ""
+	}
+	return true
+}
+
+// expandForwardingPolicyRule expands a rule block at the given user order. ID is the
+// rule_id known from the state, if any.
+func expandForwardingPolicyRule(m map[string]interface{}, order int) forwarding_rules.ForwardingRules {
+	id, _ := m["rule_id"].(int)
+	return forwarding_rules.ForwardingRules{
+		ID:                          id,
+		Name:                        m["name"].(string),
+		Description:                 m["description"].(string),
+		Order:                       order,
+		Rank:                        m["rank"].(int),
+		Type:                        m["type"].(string),
+		State:                       m["state"].(string),
+		ForwardMethod:               m["forward_method"].(string),
+		WanSelection:                m["wan_selection"].(string),
+		SourceIpGroupExclusion:      m["source_ip_group_exclusion"].(bool),
+		ResCategories:               SetToStringSlice(m["res_categories"].(*schema.Set)),
+		SrcIps:                      SetToStringSlice(m["src_ips"].(*schema.Set)),
+		DestAddresses:               SetToStringSlice(m["dest_addresses"].(*schema.Set)),
+		DestIpCategories:            SetToStringSlice(m["dest_ip_categories"].(*schema.Set)),
+		DestCountries:               processCountries(SetToStringSlice(m["dest_countries"].(*schema.Set))),
+		Locations:                   expandIDNameExtensionsFromSet(m["locations"]),
+		LocationsGroups:             expandIDNameExtensionsFromSet(m["location_groups"]),
+		ECGroups:                    expandIDNameExtensionsFromSet(m["ec_groups"]),
+		SrcIpGroups:                 expandIDNameExtensionsFromSet(m["src_ip_groups"]),
+		DestIpGroups:                expandIDNameExtensionsFromSet(m["dest_ip_groups"]),
+		NwServices:                  expandIDNameExtensionsFromSet(m["nw_services"]),
+		NwServiceGroups:             expandIDNameExtensionsFromSet(m["nw_service_groups"]),
+		AppServiceGroups:            expandIDNameExtensionsFromSet(m["app_service_groups"]),
+		ZPAApplicationSegments:      expandZPAApplicationSegmentsFromSet(m["zpa_application_segments"]),
+		ZPAApplicationSegmentGroups: expandZPAApplicationSegmentGroupsFromSet(m["zpa_application_segment_groups"]),
+		SrcWorkloadGroups:           expandIDNameExtensionsFromSet(m["src_workload_groups"]),
+		ProxyGateway:                expandIDNameFromSet(m["proxy_gateway"]),
+	}
+}
+
+func expandForwardingPolicyRules(rawRules []interface{}) []forwarding_rules.ForwardingRules {
+	result := make([]forwarding_rules.ForwardingRules, 0, len(rawRules))
+	for i, raw := range rawRules {
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		result = append(result, expandForwardingPolicyRule(m, i+1))
+	}
+	return result
+}
+
+func flattenForwardingPolicyRule(r *forwarding_rules.ForwardingRules, order int) map[string]interface{} {
+	destCountries := make([]string, len(r.DestCountries))
+	for i, country := range r.DestCountries {
+		destCountries[i] = strings.TrimPrefix(country, "COUNTRY_")
+	}
+	return map[string]interface{}{
+		"rule_id":                        r.ID,
+		"order":                          order,
+		"name":                           r.Name,
+		"description":                    r.Description,
+		"type":                           r.Type,
+		"forward_method":                 r.ForwardMethod,
+		"rank":                           r.Rank,
+		"state":                          r.State,
+		"src_ips":                        r.SrcIps,
+		"source_ip_group_exclusion":      r.SourceIpGroupExclusion,
+		"dest_addresses":                 r.DestAddresses,
+		"dest_ip_categories":             r.DestIpCategories,
+		"res_categories":                 r.ResCategories,
+		"wan_selection":                  r.WanSelection,
+		"dest_countries":                 destCountries,
+		"locations":                      flattenIDExtensionsListIDs(r.Locations),
+		"location_groups":                flattenIDExtensionsListIDs(r.LocationsGroups),
+		"ec_groups":                      flattenIDExtensionsListIDs(r.ECGroups),
+		"src_ip_groups":                  flattenIDExtensionsListIDs(r.SrcIpGroups),
+		"dest_ip_groups":                 flattenIDExtensionsListIDs(r.DestIpGroups),
+		"nw_services":                    flattenIDExtensionsListIDs(r.NwServices),
+		"nw_service_groups":              flattenIDExtensionsListIDs(r.NwServiceGroups),
+		"app_service_groups":             flattenIDExtensionsListIDs(r.AppServiceGroups),
+		"proxy_gateway":                  flattenIDNameSet(r.ProxyGateway),
+		"zpa_application_segments":       flattenZPAApplicationSegmentsSimple(r.ZPAApplicationSegments),
+		"zpa_application_segment_groups": flattenZPAApplicationSegmentGroupsSimple(r.ZPAApplicationSegmentGroups),
+		"src_workload_groups":            flattenIDExtensionsListIDs(r.SrcWorkloadGroups),
+	}
+}
+
+// userForwardingRules returns the non-predefined forwarding rules sorted by rank and
+// order, and the order slots of the whole rulebase.
+func userForwardingRules(ctx context.Context, service *zscaler.Service) ([]forwarding_rules.ForwardingRules, ruleOrderSlots, error) {
+	allRules, err := forwarding_rules.GetAll(ctx, service)
+	if err != nil {
+		return nil, ruleOrderSlots{}, err
+	}
+	result := make([]forwarding_rules.ForwardingRules, 0, len(allRules))
+	for _, r := range allRules {
+		if validatePredefinedRules(r) == nil {
+			result = append(result, r)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Rank < result[j].Rank || (result[i].Rank == result[j].Rank && result[i].Order < result[j].Order)
+	})
+	return result, newRuleOrderSlots(forwardingRuleSlots(allRules)), nil
+}
+
+// forwardingPolicyPlan is the set of API calls needed to turn the live rulebase into the
+// desired one. Upserts are in desired order; an upsert with ID 0 is a create.
+type forwardingPolicyPlan struct {
+	deletes []forwarding_rules.ForwardingRules
+	upserts []forwarding_rules.ForwardingRules
+}
+
+// planForwardingPolicy matches desired rules to live rules, first by name and then by the
+// rule_id known from the state, so that renaming a rule in place updates it. Live rules
+// that are not matched are deleted; every desired rule is created or updated at its list
+// position.
+func planForwardingPolicy(desired, live []forwarding_rules.ForwardingRules) forwardingPolicyPlan {
+	liveByName := make(map[string]forwarding_rules.ForwardingRules, len(live))
+	liveByID := make(map[int]bool, len(live))
+	for _, r := range live {
+		liveByName[r.Name] = r
+		liveByID[r.ID] = true
+	}
+
+	matched := make(map[int]bool, len(desired))
+	ids := make([]int, len(desired))
+	for i, r := range desired {
+		if existing, ok := liveByName[r.Name]; ok {
+			ids[i] = existing.ID
+			matched[existing.ID] = true
+		}
+	}
+	for i, r := range desired {
+		// The rule_id of a new block may be carried over from the block previously at its
+		// position, so it is only used when that rule isn't matched by name
+		if ids[i] == 0 && r.ID != 0 && liveByID[r.ID] && !matched[r.ID] {
+			ids[i] = r.ID
+			matched[r.ID] = true
+		}
+	}
+
+	plan := forwardingPolicyPlan{}
+	for _, r := range live {
+		if !matched[r.ID] {
+			plan.deletes = append(plan.deletes, r)
+		}
+	}
+	for i, r := range desired {
+		r.ID = ids[i]
+		plan.upserts = append(plan.upserts, r)
+	}
+	return plan
+}
+
+func forwardingRuleNames(rules []forwarding_rules.ForwardingRules) []string {
+	names := make([]string, 0, len(rules))
+	for _, r := range rules {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// unlistedForwardingRulesError refuses to create the policy over existing rules it would
+// delete, which the plan of a new resource cannot show.
+func unlistedForwardingRulesError(unlisted []forwarding_rules.ForwardingRules) error {
+	if len(unlisted) == 0 {
+		return nil
+	}
+	return fmt.Errorf("ztc_traffic_forwarding_policy owns the whole forwarding rulebase, but %d existing rules are not in its rule list: %s. "+
+		"Import the rulebase first with `terraform import <address> %s` and keep, edit or remove the rules in the plan, or delete them in the ZTC portal",
+		len(unlisted), strings.Join(forwardingRuleNames(unlisted), ", "), forwardingPolicyID)
+}
+
+func flattenForwardingRulesToDelete(rules []forwarding_rules.ForwardingRules) []interface{} {
+	result := make([]interface{}, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, map[string]interface{}{
+			"rule_id": r.ID,
+			"name":    r.Name,
+		})
+	}
+	return result
+}
+
+// unplannedForwardingRulesError refuses to delete rules that were not in the
+// rules_to_delete of the plan, such as rules created between the plan and the apply.
+func unplannedForwardingRulesError(deletes []forwarding_rules.ForwardingRules, planned []interface{}) error {
+	plannedIDs := make(map[int]bool, len(planned))
+	for _, v := range planned {
+		if m, ok := v.(map[string]interface{}); ok {
+			plannedIDs[m["rule_id"].(int)] = true
+		}
+	}
+	var unplanned []forwarding_rules.ForwardingRules
+	for _, r := range deletes {
+		if !plannedIDs[r.ID] {
+			unplanned = append(unplanned, r)
+		}
+	}
+	if len(unplanned) == 0 {
+		return nil
+	}
+	return fmt.Errorf("the forwarding rulebase changed since the plan: %d rules that are not in the rule list were not in rules_to_delete: %s. "+
+		"Run terraform plan again to review the rules that will be deleted",
+		len(unplanned), strings.Join(forwardingRuleNames(unplanned), ", "))
+}
+
+// resourceTrafficForwardingPolicyCustomizeDiff validates the rule blocks and, once they
+// are known, compares them with the live rulebase: a new policy cannot take over unlisted
+// rules, and the rules an update deletes are shown in rules_to_delete. The apply only
+// deletes the rules shown there.
+func resourceTrafficForwardingPolicyCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	rawRules := d.Get("rule").([]interface{})
+	if err := validateForwardingPolicyRules(rawRules); err != nil {
+		return err
+	}
+	zClient, ok := meta.(*Client)
+	raw := d.GetRawConfig()
+	if !ok || raw.IsNull() {
+		return nil
+	}
+	if !raw.IsWhollyKnown() {
+		// The deletions are only known once the rules are, so they are decided on apply
+		return d.SetNewComputed("rules_to_delete")
+	}
+	if err := zClient.connect(); err != nil {
+		return err
+	}
+	live, _, err := userForwardingRules(ctx, zClient.Service)
+	if err != nil {
+		return fmt.Errorf("error listing traffic forwarding rules: %v", err)
+	}
+	plan := planForwardingPolicy(expandForwardingPolicyRules(rawRules), live)
+	if d.Id() == "" {
+		if err := unlistedForwardingRulesError(plan.deletes); err != nil {
+			return err
+		}
+	}
+	return d.SetNew("rules_to_delete", flattenForwardingRulesToDelete(plan.deletes))
+}
+
+func applyForwardingPolicy(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	live, slots, err := userForwardingRules(ctx, service)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("error listing traffic forwarding rules: %v", err))
+	}
+	plan := planForwardingPolicy(expandForwardingPolicyRules(d.Get("rule").([]interface{})), live)
+	// The rules may have been created since the plan was made
+	if d.IsNewResource() {
+		if err := unlistedForwardingRulesError(plan.deletes); err != nil {
+			return diag.FromErr(err)
+		}
+	} else if rawPlan := d.GetRawPlan(); rawPlan.IsNull() || rawPlan.GetAttr("rules_to_delete").IsKnown() {
+		if err := unplannedForwardingRulesError(plan.deletes, d.Get("rules_to_delete").([]interface{})); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	for _, r := range plan.deletes {
+		log.Printf("[INFO] Deleting traffic forwarding rule %q (ID %d) not present in ztc_traffic_forwarding_policy", r.Name, r.ID)
+		if _, err := forwarding_rules.Delete(ctx, service, r.ID); err != nil {
+			return diag.FromErr(fmt.Errorf("error deleting traffic forwarding rule %d: %v", r.ID, err))
+		}
+	}
+	if len(plan.deletes) > 0 {
+		if _, slots, err = userForwardingRules(ctx, service); err != nil {
+			return diag.FromErr(fmt.Errorf("error listing traffic forwarding rules: %v", err))
+		}
+	}
+
+	// Rules are placed from the top down, so every rule lands at its final order and
+	// pushes the not yet processed rules below it. Placing a rule may also push the
+	// predefined rules that sit between user rules, in which case the slots are reloaded.
+	interleaved := slots.interleaved(len(plan.upserts))
+	for i, rule := range plan.upserts {
+		rule.Order = slots.apiOrder(i + 1)
+		if rule.ID == 0 {
+			log.Printf("[INFO] Creating traffic forwarding rule %q at order %d", rule.Name, rule.Order)
+			resp, err := forwarding_rules.Create(ctx, service, &rule)
+			if customErr := failFastOnErrorCodes(err); customErr != nil {
+				return diag.Errorf("%v", customErr)
+			}
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("error creating traffic forwarding rule %q: %v", rule.Name, err))
+			}
+			log.Printf("[INFO] Created traffic forwarding rule %q with ID %d", rule.Name, resp.ID)
+		} else {
+			log.Printf("[INFO] Updating traffic forwarding rule %q (ID %d) at order %d", rule.Name, rule.ID, rule.Order)
+			_, err := forwarding_rules.Update(ctx, service, rule.ID, &rule)
+			if customErr := failFastOnErrorCodes(err); customErr != nil {
+				return diag.Errorf("%v", customErr)
+			}
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("error updating traffic forwarding rule %q (ID %d): %v", rule.Name, rule.ID, err))
+			}
+		}
+		if interleaved {
+			if _, slots, err = userForwardingRules(ctx, service); err != nil {
+				return diag.FromErr(fmt.Errorf("error listing traffic forwarding rules: %v", err))
+			}
+		}
+	}
+	return nil
+}
+
+func activateForwardingPolicy(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if !d.Get("activate").(bool) {
+		return nil
+	}
+	service := meta.(*Client).Service
+	log.Printf("[INFO] Activating configuration after traffic forwarding policy changes")
+	if _, err := activation.UpdateActivationStatus(ctx, service, activation.ECAdminActivation{}); err != nil {
+		return diag.FromErr(fmt.Errorf("error activating configuration: %v", err))
+	}
+	return nil
+}
+
+func resourceTrafficForwardingPolicyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := applyForwardingPolicy(ctx, d, meta); diags.HasError() {
+		return diags
+	}
+	d.SetId(forwardingPolicyID)
+	if diags := activateForwardingPolicy(ctx, d, meta); diags.HasError() {
+		return diags
+	}
+	return resourceTrafficForwardingPolicyRead(ctx, d, meta)
+}
+
+func resourceTrafficForwardingPolicyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	live, slots, err := userForwardingRules(ctx, service)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	rules := make([]interface{}, 0, len(live))
+	for i := range live {
+		rules = append(rules, flattenForwardingPolicyRule(&live[i], slots.userOrder(live[i].Order)))
+	}
+	if err := d.Set("rule", rules); err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set("rules_to_delete", []interface{}{})
+	return nil
+}
+
+func resourceTrafficForwardingPolicyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Rules created outside Terraform are deleted even when the rule list is unchanged
+	if d.HasChanges("rule", "rules_to_delete") {
+		if diags := applyForwardingPolicy(ctx, d, meta); diags.HasError() {
+			return diags
+		}
+	}
+	// Turning activate on activates the current configuration even without rule changes
+	if d.HasChanges("rule", "rules_to_delete", "activate") {
+		if diags := activateForwardingPolicy(ctx, d, meta); diags.HasError() {
+			return diags
+		}
+	}
+	return resourceTrafficForwardingPolicyRead(ctx, d, meta)
+}
+
+// resourceTrafficForwardingPolicyDelete leaves the rules in place: destroying the policy
+// must not wipe the rulebase. Emptying the rule list deletes the rules instead.
+func resourceTrafficForwardingPolicyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  "forwarding rules were not deleted",
+			Detail:   "ztc_traffic_forwarding_policy has been removed from the Terraform state, but its forwarding rules still exist in the ZTC portal. To delete them, remove the rule blocks and apply before destroying the resource.",
+		},
+	}
+}
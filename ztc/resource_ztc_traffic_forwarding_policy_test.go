@@ -0,0 +1,141 @@
+package ztc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/forwarding_rules"
+)
+
+func TestPlanForwardingPolicy(t *testing.T) {
+	live := []forwarding_rules.ForwardingRules{
+		{ID: 10, Name: "a", Order: 1},
+		{ID: 20, Name: "b", Order: 2},
+		{ID: 30, Name: "c", Order: 3},
+	}
+	desired := []forwarding_rules.ForwardingRules{
+		{Name: "c", Order: 1},
+		{Name: "new", Order: 2},
+		{Name: "a", Order: 3},
+	}
+
+	plan := planForwardingPolicy(desired, live)
+
+	if len(plan.deletes) != 1 || plan.deletes[0].ID != 20 {
+		t.Errorf("expected rule 20 to be deleted, got %v", plan.deletes)
+	}
+	expected := []struct {
+		name  string
+		id    int
+		order int
+	}{
+		{"c", 30, 1},
+		{"new", 0, 2},
+		{"a", 10, 3},
+	}
+	if len(plan.upserts) != len(expected) {
+		t.Fatalf("expected %d upserts, got %d", len(expected), len(plan.upserts))
+	}
+	for i, e := range expected {
+		u := plan.upserts[i]
+		if u.Name != e.name || u.ID != e.id || u.Order != e.order {
+			t.Errorf("upsert %d: expected %s/%d/%d, got %s/%d/%d", i, e.name, e.id, e.order, u.Name, u.ID, u.Order)
+		}
+	}
+}
+
+func TestPlanForwardingPolicy_MatchesByRuleID(t *testing.T) {
+	live := []forwarding_rules.ForwardingRules{
+		{ID: 10, Name: "a", Order: 1},
+		{ID: 20, Name: "b", Order: 2},
+	}
+	cases := []struct {
+		name    string
+		desired []forwarding_rules.ForwardingRules
+		ids     []int
+	}{
+		// The rule_id in the state stays with its block, so a renamed rule is updated
+		{"rename", []forwarding_rules.ForwardingRules{{ID: 10, Name: "a"}, {ID: 20, Name: "b2"}}, []int{10, 20}},
+		// A block inserted at the top takes over the rule_id of the block previously at
+		// its position, which is already claimed by name
+		{"insert at top", []forwarding_rules.ForwardingRules{{ID: 10, Name: "new"}, {ID: 20, Name: "a"}, {Name: "b"}}, []int{0, 10, 20}},
+	}
+	for _, c := range cases {
+		plan := planForwardingPolicy(c.desired, live)
+		if len(plan.deletes) != 0 {
+			t.Errorf("%s: expected no deletes, got %v", c.name, plan.deletes)
+		}
+		for i, id := range c.ids {
+			if plan.upserts[i].ID != id {
+				t.Errorf("%s: upsert %d (%s): expected ID %d, got %d", c.name, i, plan.upserts[i].Name, id, plan.upserts[i].ID)
+			}
+		}
+	}
+}
+
+func TestUnlistedForwardingRulesError(t *testing.T) {
+	if err := unlistedForwardingRulesError(nil); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := unlistedForwardingRulesError([]forwarding_rules.ForwardingRules{{ID: 20, Name: "b"}})
+	if err == nil || !strings.Contains(err.Error(), "terraform import") || !strings.Contains(err.Error(), "b") {
+		t.Errorf("expected an import hint naming rule b, got %v", err)
+	}
+}
+
+func TestUnplannedForwardingRulesError(t *testing.T) {
+	desired := []forwarding_rules.ForwardingRules{{Name: "a"}}
+	live := []forwarding_rules.ForwardingRules{
+		{ID: 10, Name: "a", Order: 1},
+		{ID: 20, Name: "b", Order: 2},
+	}
+	planned := flattenForwardingRulesToDelete(planForwardingPolicy(desired, live).deletes)
+
+	if err := unplannedForwardingRulesError(planForwardingPolicy(desired, live).deletes, planned); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	// A rule created between the plan and the apply is not deleted
+	live = append(live, forwarding_rules.ForwardingRules{ID: 30, Name: "c", Order: 3})
+	err := unplannedForwardingRulesError(planForwardingPolicy(desired, live).deletes, planned)
+	if err == nil || !strings.Contains(err.Error(), "rules_to_delete: c.") {
+		t.Errorf("expected an error naming only rule c, got %v", err)
+	}
+}
+
+func TestValidateForwardingPolicyRules(t *testing.T) {
+	rule := func(name, forwardMethod string, rank int) map[string]interface{} {
+		return map[string]interface{}{
+			"name":           name,
+			"forward_method": forwardMethod,
+			"rank":           rank,
+		}
+	}
+
+	cases := []struct {
+		name    string
+		rules   []interface{}
+		wantErr string
+	}{
+		{"valid", []interface{}{rule("a", "DIRECT", 1), rule("b", "ZIA", 7)}, ""},
+		{"duplicate names", []interface{}{rule("a", "DIRECT", 7), rule("a", "ZIA", 7)}, "must be unique"},
+		{"decreasing rank", []interface{}{rule("a", "DIRECT", 7), rule("b", "ZIA", 1)}, "ascending rank"},
+		{"predefined rule", []interface{}{rule("ZPA Forwarding Rule", "ECZPA", 7)}, "predefined rule"},
+	}
+	for _, c := range cases {
+		err := validateForwardingPolicyRules(c.rules)
+		if c.wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+		}
+		if c.wantErr != "" && (err == nil || !strings.Contains(err.Error(), c.wantErr)) {
+			t.Errorf("%s: expected error containing %q, got %v", c.name, c.wantErr, err)
+		}
+	}
+
+	eczpa := rule("a", "ECZPA", 7)
+	eczpa["dest_addresses"] = schema.NewSet(schema.HashString, []interface{}{"10.0.0.1"})
+	if err := validateForwardingPolicyRules([]interface{}{eczpa}); err == nil {
+		t.Error("expected dest_addresses to be rejected for ECZPA")
+	}
+}
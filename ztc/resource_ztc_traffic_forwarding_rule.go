@@ -8,7 +8,6 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -19,11 +18,6 @@ import (
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/forwarding_rules"
 )
 
-var (
-	forwardingControlLock          sync.Mutex
-	forwardingControlStartingOrder int
-)
-
 func getRule(ctx context.Context, service *zscaler.Service, id int) (*forwarding_rules.ForwardingRules, error) {
 	allRules, err := forwarding_rules.GetAll(ctx, service)
 	if err != nil {
@@ -35,13 +29,15 @@ func getRule(ctx context.Context, service *zscaler.Service, id int) (*forwarding
 			return &rule, nil
 		}
 	}
-	return nil, fmt.Errorf("rule with ID %d not found", id)
+	return nil, fmt.Errorf("rule with ID %d %w", id, errRuleNotFound)
 }
 
 func resourceTrafficForwardingRule() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceTrafficForwardingRuleCreate,
-		ReadContext:   resourceTrafficForwardingRuleRead,
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return readRuleWithOrderDrift(ctx, d, meta, "ztc_traffic_forwarding_rule", resourceTrafficForwardingRuleRead)
+		},
 		UpdateContext: resourceTrafficForwardingRuleUpdate,
 		DeleteContext: resourceTrafficForwardingRuleDelete,
 		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
@@ -60,34 +56,18 @@ func resourceTrafficForwardingRule() *schema.Resource {
 				return true
 			}
 
-			// If forward_method is ECZPA, certain attributes cannot be set
-			if forwardMethod == "ECZPA" {
-				prohibitedAttrs := []string{
-					"dest_addresses",
-					"dest_countries",
-					"dest_ip_groups",
-					"dest_ip_categories",
-					"proxy_gateway",
-					"nw_services",
-					"nw_service_groups",
-					"app_service_groups",
-				}
-				for _, attr := range prohibitedAttrs {
-					if isSet(attr) {
-						return fmt.Errorf("%s attribute cannot be set when forward_method is 'ECZPA'", attr)
-					}
+			for _, attr := range forwardMethodProhibitedAttributes(forwardMethod) {
+				if isSet(attr) {
+					return fmt.Errorf("%s attribute cannot be set when forward_method is '%s'", attr, forwardMethod)
 				}
 			}
 
-			// If forward_method is ZIA, DIRECT, LOCAL_SWITCH, or DROP, ZPA-related attributes cannot be set
-			if forwardMethod == "ZIA" || forwardMethod == "DIRECT" || forwardMethod == "LOCAL_SWITCH" || forwardMethod == "DROP" {
-				prohibitedAttrs := []string{
-					"zpa_application_segments",
-					"zpa_application_segment_groups",
-				}
-				for _, attr := range prohibitedAttrs {
-					if isSet(attr) {
-						return fmt.Errorf("%s attribute cannot be set when forward_method is '%s'", attr, forwardMethod)
+			// Relatively placed rules get their order at apply time, and are moved back next
+			// to their anchor when the last read found them elsewhere
+			if d.Get("place_before").(string) != "" || d.Get("place_after").(string) != "" {
+				if d.HasChange("place_before") || d.HasChange("place_after") || (d.Id() != "" && d.Get("order_drift").(bool)) {
+					if err := d.SetNewComputed("order"); err != nil {
+						return err
 					}
 				}
 			}
@@ -96,7 +76,9 @@ func resourceTrafficForwardingRule() *schema.Resource {
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(60 * time.Minute),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
 			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -120,7 +102,7 @@ func resourceTrafficForwardingRule() *schema.Resource {
 			},
 		},
 
-		Schema: map[string]*schema.Schema{
+		Schema: MergeSchema(map[string]*schema.Schema{
 			"id": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -131,6 +113,11 @@ func resourceTrafficForwardingRule() *schema.Resource {
 				Computed:    true,
 				Description: "A unique identifier assigned to the forwarding rule",
 			},
+			"predefined": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Indicates that this is a predefined rule created by Zscaler. Predefined rules can be imported and updated, but keep the position assigned by the API and are only removed from state on destroy",
+			},
 			"name": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -173,9 +160,23 @@ func resourceTrafficForwardingRule() *schema.Resource {
 				}, false),
 			},
 			"order": {
-				Type:        schema.TypeInt,
-				Required:    true,
-				Description: "The order of execution for the forwarding rule order",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"order", "place_before", "place_after"},
+				Description:  "The order of execution of the rule among the user-defined forwarding rules. Predefined rules are not counted",
+			},
+			"place_before": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"order", "place_before", "place_after"},
+				Description:  "ID or name of an existing forwarding rule. The rule is placed directly before it, resolved against the live rule list at apply time",
+			},
+			"place_after": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"order", "place_before", "place_after"},
+				Description:  "ID or name of an existing forwarding rule. The rule is placed directly after it, resolved against the live rule list at apply time",
 			},
 			"rank": {
 				Type:        schema.TypeInt,
@@ -245,21 +246,112 @@ func resourceTrafficForwardingRule() *schema.Resource {
 			"zpa_application_segment_groups": setIDsSchemaTypeCustom(intPtr(255), "List of ZPA Application Segment Groups for which this rule is applicable. This field is applicable only for the ECZPA forwarding method (used for Zscaler Cloud Connector)."),
 			"dest_countries":                 getISOCountryCodes(),
 			"src_workload_groups":            setIDsSchemaTypeCustom(nil, "The list of preconfigured workload groups to which the policy must be applied"),
-		},
+		}, ruleOrderDriftSchema()),
 	}
 }
 
+// forwardMethodProhibitedAttributes returns the attributes that cannot be set for the given forward_method.
+func forwardMethodProhibitedAttributes(forwardMethod string) []string {
+	switch forwardMethod {
+	// If forward_method is ECZPA, certain attributes cannot be set
+	case "ECZPA":
+		return []string{
+			"dest_addresses",
+			"dest_countries",
+			"dest_ip_groups",
+			"dest_ip_categories",
+			"proxy_gateway",
+			"nw_services",
+			"nw_service_groups",
+			"app_service_groups",
+		}
+	// If forward_method is ZIA, DIRECT, LOCAL_SWITCH, or DROP, ZPA-related attributes cannot be set
+	case "ZIA", "DIRECT", "LOCAL_SWITCH", "DROP":
+		return []string{
+			"zpa_application_segments",
+			"zpa_application_segment_groups",
+		}
+	}
+	return nil
+}
+
 func validatePredefinedRules(req forwarding_rules.ForwardingRules) error {
-	if req.Name == "ZPA Forwarding Rule" || req.Name == "Direct rule for Zscaler Cloud Endpoints" {
+	if isPredefinedRuleName("forwarding_control_rule", req.Name) {
 		return fmt.Errorf("predefined rule '%s' cannot be deleted", req.Name)
 	}
-	if req.Name == "Direct rule for WAN Destinations Group" || req.Name == "Direct rule for LAN Destinations Group" {
-		return fmt.Errorf("predefined rule '%s' cannot be deleted", req.Name)
+	return nil
+}
+
+// forwardingRulePlacement returns the anchor of a relatively placed rule and whether the
+// rule goes after it. ok is false when the rule uses an absolute order.
+func forwardingRulePlacement(d *schema.ResourceData) (anchor string, after bool, ok bool) {
+	if v := d.Get("place_before").(string); v != "" {
+		return v, false, true
 	}
-	if req.Name == "Client Connector to ZPA" || req.Name == "ZPA Pool For Stray Traffic" {
-		return fmt.Errorf("predefined rule '%s' cannot be deleted", req.Name)
+	if v := d.Get("place_after").(string); v != "" {
+		return v, true, true
 	}
-	return nil
+	return "", false, false
+}
+
+// resolveForwardingRulePlacement resolves place_before/place_after against the given
+// forwarding rules and returns a user order. Predefined rules cannot be used as anchors.
+func resolveForwardingRulePlacement(d *schema.ResourceData, selfID int, list []forwarding_rules.ForwardingRules) (OrderRule, error) {
+	anchor, after, _ := forwardingRulePlacement(d)
+	slots := newRuleOrderSlots(forwardingRuleSlots(list))
+	anchors := make([]ruleAnchor, 0, len(list))
+	for _, r := range list {
+		if validatePredefinedRules(r) != nil {
+			continue
+		}
+		anchors = append(anchors, ruleAnchor{ID: r.ID, Name: r.Name, Order: slots.userOrder(r.Order), Rank: r.Rank})
+	}
+	var configuredRank *int
+	if raw := d.GetRawConfig(); !raw.IsNull() && raw.IsKnown() && raw.Type().IsObjectType() {
+		configuredRank = rawConfigInt(raw, "rank")
+	}
+	return resolveRelativeRuleOrder(anchor, after, selfID, configuredRank, anchors)
+}
+
+// forwardingRuleSlots returns the position of every forwarding rule in the API order.
+func forwardingRuleSlots(list []forwarding_rules.ForwardingRules) []ruleSlot {
+	slots := make([]ruleSlot, 0, len(list))
+	for _, r := range list {
+		slots = append(slots, ruleSlot{ID: r.ID, Order: r.Order, Predefined: validatePredefinedRules(r) != nil})
+	}
+	return slots
+}
+
+// forwardingRuleSlotLister returns the function that lists the slots of the live forwarding rules.
+func forwardingRuleSlotLister(service *zscaler.Service) func(ctx context.Context) ([]ruleSlot, error) {
+	return func(ctx context.Context) ([]ruleSlot, error) {
+		list, err := forwarding_rules.GetAll(ctx, service)
+		if err != nil {
+			return nil, err
+		}
+		return forwardingRuleSlots(list), nil
+	}
+}
+
+// forwardingRuleReorder returns the functions with which forwarding rules are moved to
+// their user order, skipping the orders held by predefined rules.
+func forwardingRuleReorder(rules *listrules, service *zscaler.Service) (func(ctx context.Context) (int, error), func(ctx context.Context, id int, order OrderRule) error) {
+	return userOrderReorder(rules, "forwarding_control_rule", forwardingRuleSlotLister(service),
+		func(ctx context.Context, id int, order OrderRule) error {
+			rule, err := getRule(ctx, service, id)
+			if err != nil {
+				return err
+			}
+
+			// to avoid the STALE_CONFIGURATION_ERROR
+			rule.LastModifiedTime = 0
+			rule.LastModifiedBy = nil
+			rule.Order = order.Order
+			rule.Rank = order.Rank
+			_, err = forwarding_rules.Update(ctx, service, id, rule)
+			return err
+		},
+	)
 }
 
 func resourceTrafficForwardingRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -267,33 +359,45 @@ func resourceTrafficForwardingRuleCreate(ctx context.Context, d *schema.Resource
 	service := zClient.Service
 
 	req := expandForwardingControlRule(d)
+	if err := reservedRuleNameError("forwarding_control_rule", req.Name); err != nil {
+		return diag.FromErr(err)
+	}
 	log.Printf("[INFO] Creating ztc traffic forwarding rule\n%+v\n", req)
 
 	start := time.Now()
 
 	for {
-		forwardingControlLock.Lock()
-		if forwardingControlStartingOrder == 0 {
+		startingOrder := zClient.rules.startingOrder("forwarding_control_rule", func() int {
+			highest := 0
 			list, _ := forwarding_rules.GetAll(ctx, service)
 			for _, r := range list {
-				if r.Order > forwardingControlStartingOrder {
-					forwardingControlStartingOrder = r.Order
+				if r.Order > highest {
+					highest = r.Order
 				}
 			}
-			if forwardingControlStartingOrder == 0 {
-				forwardingControlStartingOrder = 1
-			}
-		}
-		forwardingControlLock.Unlock()
+			return highest
+		})
 		startWithoutLocking := time.Now()
 
 		intendedOrder := req.Order
 		intendedRank := req.Rank
+		_, _, placed := forwardingRulePlacement(d)
+		if placed {
+			list, err := forwarding_rules.GetAll(ctx, service)
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("error listing traffic forwarding rules to resolve placement: %s", err))
+			}
+			resolved, err := resolveForwardingRulePlacement(d, 0, list)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			intendedOrder, intendedRank = resolved.Order, resolved.Rank
+		}
 		if intendedRank < 7 {
 			// always start rank 7 rules at the next available order after all ranked rules
 			req.Rank = 7
 		}
-		req.Order = forwardingControlStartingOrder
+		req.Order = startingOrder
 		resp, err := forwarding_rules.Create(ctx, service, &req)
 
 		// Fail immediately if INVALID_INPUT_ARGUMENT is detected
@@ -314,43 +418,32 @@ func resourceTrafficForwardingRuleCreate(ctx context.Context, d *schema.Resource
 		log.Printf("[INFO] Created ztc traffic forwarding rule request. Took: %s, without locking: %s, ID: %v\n", time.Since(start), time.Since(startWithoutLocking), resp)
 		// Use separate resource type for rank 7 rules to avoid mixing with ranked rules
 		resourceType := "forwarding_control_rule"
+		zClient.rules.invalidateRuleSlots("forwarding_control_rule")
+		getCount, updateOrder := forwardingRuleReorder(zClient.rules, service)
 
-		reorderWithBeforeReorder(
+		zClient.rules.reorderWithBeforeReorder(
+			ctx,
 			OrderRule{Order: intendedOrder, Rank: intendedRank},
 			resp.ID,
 			resourceType,
-			func() (int, error) {
-				allRules, err := forwarding_rules.GetAll(ctx, service)
-				if err != nil {
-					return 0, err
-				}
-				// Count all rules including predefined ones for proper ordering
-				return len(allRules), nil
-			},
-			func(id int, order OrderRule) error {
-				rule, err := getRule(ctx, service, id)
-				if err != nil {
-					return err
-				}
-
-				// to avoid the STALE_CONFIGURATION_ERROR
-				rule.LastModifiedTime = 0
-				rule.LastModifiedBy = nil
-				rule.Order = order.Order
-				rule.Rank = order.Rank
-				_, err = forwarding_rules.Update(ctx, service, id, rule)
-				return err
-			},
+			getCount,
+			updateOrder,
 			nil, // Remove beforeReorder function to avoid adding too many rules to the map
 		)
 
 		d.SetId(strconv.Itoa(resp.ID))
 		_ = d.Set("rule_id", resp.ID)
+		recordIntendedRuleOrder(d)
+		if placed {
+			recordResolvedRuleOrder(d, OrderRule{Order: intendedOrder, Rank: intendedRank})
+		}
 
-		markOrderRuleAsDone(resp.ID, resourceType)
-		waitForReorder(resourceType)
+		zClient.rules.markOrderRuleAsDone(resp.ID, resourceType)
+		if err := zClient.rules.waitForReorder(ctx, resourceType, resp.ID); err != nil {
+			return diag.FromErr(fmt.Errorf("error reordering traffic forwarding rule %d: %w", resp.ID, err))
+		}
 
-		return resourceTrafficForwardingRuleRead(ctx, d, meta)
+		return readRuleWithOrderDrift(ctx, d, meta, "ztc_traffic_forwarding_rule", resourceTrafficForwardingRuleRead)
 	}
 }
 
@@ -363,10 +456,17 @@ func resourceTrafficForwardingRuleRead(ctx context.Context, d *schema.ResourceDa
 		return diag.FromErr(fmt.Errorf("no zia firewall filtering rule id is set"))
 	}
 
-	resp, err := getRule(ctx, service, id)
+	list, err := forwarding_rules.GetAll(ctx, service)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	var resp *forwarding_rules.ForwardingRules
+	for i := range list {
+		if list[i].ID == id {
+			resp = &list[i]
+			break
+		}
+	}
 
 	// Rule not found
 	if resp == nil {
@@ -374,6 +474,11 @@ func resourceTrafficForwardingRuleRead(ctx context.Context, d *schema.ResourceDa
 		d.SetId("")
 		return nil
 	}
+	predefined := validatePredefinedRules(*resp) != nil
+	order := resp.Order
+	if !predefined {
+		order = newRuleOrderSlots(forwardingRuleSlots(list)).userOrder(resp.Order)
+	}
 
 	processedDestCountries := make([]string, len(resp.DestCountries))
 	for i, country := range resp.DestCountries {
@@ -386,10 +491,20 @@ func resourceTrafficForwardingRuleRead(ctx context.Context, d *schema.ResourceDa
 	_ = d.Set("name", resp.Name)
 	_ = d.Set("description", resp.Description)
 	_ = d.Set("forward_method", resp.ForwardMethod)
-	_ = d.Set("order", resp.Order)
+	_ = d.Set("order", order)
 	_ = d.Set("rank", resp.Rank)
+	if _, _, placed := forwardingRulePlacement(d); placed {
+		// A relatively placed rule is intended to sit next to its anchor wherever the anchor is now
+		if resolved, err := resolveForwardingRulePlacement(d, resp.ID, list); err == nil {
+			_ = d.Set("intended_order", resolved.Order)
+		} else {
+			log.Printf("[WARN] unable to resolve placement of traffic forwarding rule %d: %v", resp.ID, err)
+		}
+	}
+	setRuleOrderDrift(d, order, resp.Rank)
 	_ = d.Set("state", resp.State)
 	_ = d.Set("type", resp.Type)
+	_ = d.Set("predefined", predefined)
 	_ = d.Set("src_ips", resp.SrcIps)
 	_ = d.Set("dest_addresses", resp.DestAddresses)
 	_ = d.Set("dest_ip_categories", resp.DestIpCategories)
@@ -458,16 +573,51 @@ func resourceTrafficForwardingRuleUpdate(ctx context.Context, d *schema.Resource
 	}
 	log.Printf("[INFO] Updating traffic forwarding rule ID: %v\n", id)
 	req := expandForwardingControlRule(d)
+	if d.HasChange("name") {
+		if err := reservedRuleNameError("forwarding_control_rule", req.Name); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
 	existingRules, err := forwarding_rules.GetAll(ctx, service)
 	if err != nil {
 		log.Printf("[ERROR] error getting all traffic forwarding rules: %v", err)
 	}
+	for _, r := range existingRules {
+		if r.ID == id && validatePredefinedRules(r) != nil {
+			// Predefined rules keep the position assigned by the API and are never reordered
+			req.Order = r.Order
+			req.Rank = r.Rank
+			if _, err := forwarding_rules.Update(ctx, service, id, &req); err != nil {
+				return diag.FromErr(fmt.Errorf("error updating predefined traffic forwarding rule %d: %v", id, err))
+			}
+			return resourceTrafficForwardingRuleRead(ctx, d, meta)
+		}
+		if r.ID == id && isRuleMetadataOnlyUpdate(d) {
+			// Only the state or metadata changed: keep the current position and skip the reorder
+			log.Printf("[INFO] Updating traffic forwarding rule %d in place at order %d, rank %d\n", id, r.Order, r.Rank)
+			req.Order = r.Order
+			req.Rank = r.Rank
+			if _, err := forwarding_rules.Update(ctx, service, id, &req); err != nil {
+				return diag.FromErr(fmt.Errorf("error updating traffic forwarding rule %d: %v", id, err))
+			}
+			return readRuleWithOrderDrift(ctx, d, meta, "ztc_traffic_forwarding_rule", resourceTrafficForwardingRuleRead)
+		}
+	}
 	sort.Slice(existingRules, func(i, j int) bool {
 		return existingRules[i].Rank < existingRules[j].Rank || (existingRules[i].Rank == existingRules[j].Rank && existingRules[i].Order < existingRules[j].Order)
 	})
+	recordIntendedRuleOrder(d)
 	intendedOrder := req.Order
 	intendedRank := req.Rank
+	if _, _, placed := forwardingRulePlacement(d); placed {
+		resolved, err := resolveForwardingRulePlacement(d, id, existingRules)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		intendedOrder, intendedRank = resolved.Order, resolved.Rank
+		recordResolvedRuleOrder(d, resolved)
+	}
 	nextAvailableOrder := existingRules[len(existingRules)-1].Order
 	// always start rank 7 rules at the next available order after all ranked rules
 	req.Rank = 7
@@ -485,36 +635,20 @@ func resourceTrafficForwardingRuleUpdate(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
-	reorderWithBeforeReorder(OrderRule{Order: intendedOrder, Rank: intendedRank}, req.ID, "forwarding_control_rule",
-		func() (int, error) {
-			allRules, err := forwarding_rules.GetAll(ctx, service)
-			if err != nil {
-				return 0, err
-			}
-			// Count all rules including predefined ones for proper ordering
-			return len(allRules), nil
-		},
-		func(id int, order OrderRule) error {
-			rule, err := getRule(ctx, service, id)
-			if err != nil {
-				return err
-			}
-
-			// to avoid the STALE_CONFIGURATION_ERROR
-			rule.LastModifiedTime = 0
-			rule.LastModifiedBy = nil
-			rule.Order = order.Order
-			rule.Rank = order.Rank
-			_, err = forwarding_rules.Update(ctx, service, id, rule)
-			return err
-		},
+	zClient.rules.invalidateRuleSlots("forwarding_control_rule")
+	getCount, updateOrder := forwardingRuleReorder(zClient.rules, service)
+	zClient.rules.reorderWithBeforeReorder(ctx, OrderRule{Order: intendedOrder, Rank: intendedRank}, req.ID, "forwarding_control_rule",
+		getCount,
+		updateOrder,
 		nil, // Remove beforeReorder function to avoid adding too many rules to the map
 	)
 
-	markOrderRuleAsDone(req.ID, "forwarding_control_rule")
-	waitForReorder("forwarding_control_rule")
+	zClient.rules.markOrderRuleAsDone(req.ID, "forwarding_control_rule")
+	if err := zClient.rules.waitForReorder(ctx, "forwarding_control_rule", req.ID); err != nil {
+		return diag.FromErr(fmt.Errorf("error reordering traffic forwarding rule %d: %w", req.ID, err))
+	}
 
-	return resourceTrafficForwardingRuleRead(ctx, d, meta)
+	return readRuleWithOrderDrift(ctx, d, meta, "ztc_traffic_forwarding_rule", resourceTrafficForwardingRuleRead)
 }
 
 func resourceTrafficForwardingRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -526,10 +660,23 @@ func resourceTrafficForwardingRuleDelete(ctx context.Context, d *schema.Resource
 		return diag.FromErr(fmt.Errorf("traffic forwarding rule ID not set: %v", id))
 	}
 
+	rule, err := getRule(ctx, service, id)
+	if isRuleNotFound(err) {
+		log.Printf("[WARN] Traffic forwarding rule %d no longer exists", id)
+		d.SetId("")
+		return nil
+	}
+	// Predefined rules cannot be deleted; drop them from state with a warning instead
+	if err := predefinedRuleDeleteCheck(d, err, func() error { return validatePredefinedRules(*rule) }); err != nil {
+		d.SetId("")
+		return predefinedRuleDeleteDiagnostics("ztc_traffic_forwarding_rule", id, err)
+	}
+
 	log.Printf("[INFO] Deleting traffic forwarding rule ID: %v", id)
 	if _, err := forwarding_rules.Delete(ctx, service, id); err != nil {
 		return diag.FromErr(fmt.Errorf("error deleting traffic forwarding rule %d: %v", id, err))
 	}
+	zClient.rules.invalidateRuleSlots("forwarding_control_rule")
 
 	d.SetId("")
 	log.Printf("[INFO] Traffic forwarding rule deleted")
@@ -542,7 +689,10 @@ func expandForwardingControlRule(d *schema.ResourceData) forwarding_rules.Forwar
 
 	// Retrieve the order and fallback to 1 if it's 0
 	order := d.Get("order").(int)
-	if order == 0 {
+	if _, _, placed := forwardingRulePlacement(d); placed {
+		// The order of relatively placed rules is resolved at apply time
+		order = 1
+	} else if order == 0 {
 		log.Printf("[WARN] expandForwardingControlRule: Rule ID %d has order=0. Falling back to order=1", id)
 		order = 1
 	}
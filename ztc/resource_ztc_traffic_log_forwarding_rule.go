@@ -8,31 +8,30 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/errorx"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/traffic_log_rules"
 )
 
-var (
-	trafficForwardingLogLock          sync.Mutex
-	trafficForwardingLogStartingOrder int
-)
-
 func resourceTrafficForwardingLogRule() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceTrafficForwardingLogRuleRuleCreate,
-		ReadContext:   resourceTrafficForwardingLogRuleRuleRead,
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return readRuleWithOrderDrift(ctx, d, meta, "ztc_traffic_forwarding_log_rule", resourceTrafficForwardingLogRuleRuleRead)
+		},
 		UpdateContext: resourceTrafficForwardingLogRuleRuleUpdate,
 		DeleteContext: resourceTrafficForwardingLogRuleRuleDelete,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(60 * time.Minute),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
 			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -56,7 +55,7 @@ func resourceTrafficForwardingLogRule() *schema.Resource {
 			},
 		},
 
-		Schema: map[string]*schema.Schema{
+		Schema: MergeSchema(map[string]*schema.Schema{
 			"id": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -67,6 +66,11 @@ func resourceTrafficForwardingLogRule() *schema.Resource {
 				Computed:    true,
 				Description: "A unique identifier assigned to the forwarding rule",
 			},
+			"predefined": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Indicates that this is a predefined rule created by Zscaler. Predefined rules can be imported and updated, but keep the position assigned by the API and are only removed from state on destroy",
+			},
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -85,7 +89,7 @@ func resourceTrafficForwardingLogRule() *schema.Resource {
 			"order": {
 				Type:        schema.TypeInt,
 				Optional:    true,
-				Description: "The order of execution for the forwarding rule order",
+				Description: "The order of execution of the rule among the user-defined log forwarding rules. Predefined rules are not counted",
 			},
 
 			"rank": {
@@ -101,20 +105,93 @@ func resourceTrafficForwardingLogRule() *schema.Resource {
 					"ECSELF",
 				}, false),
 			},
-			"ec_groups":     setIDsSchemaTypeCustom(intPtr(32), "Name-ID pairs of the Zscaler Cloud Connector groups to which the forwarding rule applies"),
-			"locations":     setIDsSchemaTypeCustom(intPtr(8), "Name-ID pairs of the locations to which the forwarding rule applies. If not set, the rule is applied to all locations."),
-			"proxy_gateway": setIdNameSchemaCustom(1, "The proxy gateway for which the rule is applicable. This field is applicable only for the Proxy Chaining forwarding method."),
-		},
+			"src_ips": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "User-defined source IP addresses for which the rule is applicable. If not set, the rule is not restricted to a specific source IP address.",
+			},
+			"dest_addresses": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "List of destination IP addresses or FQDNs for which the rule is applicable. CIDR notation can be used for destination IP addresses. If not set, the rule is not restricted to a specific destination addresses unless specified by destCountries, destIpGroups, or destIpCategories.",
+			},
+			"dest_ip_categories": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "List of destination IP categories to which the rule applies. If not set, the rule is not restricted to specific destination IP categories.",
+			},
+			"res_categories": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "List of destination domain categories to which the rule applies",
+			},
+			"ec_groups":           setIDsSchemaTypeCustom(intPtr(32), "Name-ID pairs of the Zscaler Cloud Connector groups to which the forwarding rule applies"),
+			"locations":           setIDsSchemaTypeCustom(intPtr(8), "Name-ID pairs of the locations to which the forwarding rule applies. If not set, the rule is applied to all locations."),
+			"location_groups":     setIDsSchemaTypeCustom(intPtr(32), "Name-ID pairs of the location groups to which the forwarding rule applies"),
+			"src_ip_groups":       setIDsSchemaTypeCustom(nil, "Source IP address groups for which the rule is applicable. If not set, the rule is not restricted to a specific source IP address group"),
+			"dest_ip_groups":      setIDsSchemaTypeCustom(nil, "User-defined destination IP address groups to which the rule is applied. If not set, the rule is not restricted to a specific destination IP address group"),
+			"nw_services":         setIDsSchemaTypeCustom(intPtr(1024), "User-defined network services to which the rule applies. If not set, the rule is not restricted to a specific network service."),
+			"nw_service_groups":   setIDsSchemaTypeCustom(nil, "User-defined network service group to which the rule applies. If not set, the rule is not restricted to a specific network service group."),
+			"app_service_groups":  setIDsSchemaTypeCustom(nil, "list of application service groups"),
+			"src_workload_groups": setIDsSchemaTypeCustom(nil, "The list of preconfigured workload groups to which the policy must be applied"),
+			"dest_countries":      getISOCountryCodes(),
+			"proxy_gateway":       setIdNameSchemaCustom(1, "The proxy gateway for which the rule is applicable. This field is applicable only for the Proxy Chaining forwarding method."),
+		}, ruleOrderDriftSchema()),
 	}
 }
 
 func validatePredefinedLogRules(req traffic_log_rules.ECTrafficLogRules) error {
-	if req.Name == "ZPA Resolver" || req.Name == "Redirect Resolution of Zscaler Domains to WAN CTR" {
+	if req.DefaultRule {
 		return fmt.Errorf("predefined rule '%s' cannot be deleted", req.Name)
 	}
 	return nil
 }
 
+// logRuleSlots returns the position of every log forwarding rule in the API order.
+func logRuleSlots(list []traffic_log_rules.ECTrafficLogRules) []ruleSlot {
+	slots := make([]ruleSlot, 0, len(list))
+	for _, r := range list {
+		slots = append(slots, ruleSlot{ID: r.ID, Order: r.Order, Predefined: validatePredefinedLogRules(r) != nil})
+	}
+	return slots
+}
+
+// logRuleSlotLister returns the function that lists the slots of the live log forwarding rules.
+func logRuleSlotLister(service *zscaler.Service) func(ctx context.Context) ([]ruleSlot, error) {
+	return func(ctx context.Context) ([]ruleSlot, error) {
+		list, err := traffic_log_rules.GetAll(ctx, service)
+		if err != nil {
+			return nil, err
+		}
+		return logRuleSlots(list), nil
+	}
+}
+
+// logRuleReorder returns the functions with which log forwarding rules are moved to
+// their user order, skipping the orders held by predefined rules.
+func logRuleReorder(rules *listrules, service *zscaler.Service) (func(ctx context.Context) (int, error), func(ctx context.Context, id int, order OrderRule) error) {
+	return userOrderReorder(rules, "traffic_forwarding_log_rule", logRuleSlotLister(service),
+		func(ctx context.Context, id int, order OrderRule) error {
+			rule, err := traffic_log_rules.Get(ctx, service, id)
+			if err != nil {
+				return err
+			}
+
+			// to avoid the STALE_CONFIGURATION_ERROR
+			rule.LastModifiedTime = 0
+			rule.LastModifiedBy = nil
+			rule.Order = order.Order
+			rule.Rank = order.Rank
+			_, err = traffic_log_rules.Update(ctx, service, id, rule)
+			return err
+		},
+	)
+}
+
 func resourceTrafficForwardingLogRuleRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	zClient := meta.(*Client)
 	service := zClient.Service
@@ -125,19 +202,16 @@ func resourceTrafficForwardingLogRuleRuleCreate(ctx context.Context, d *schema.R
 	start := time.Now()
 
 	for {
-		trafficForwardingLogLock.Lock()
-		if trafficForwardingLogStartingOrder == 0 {
+		startingOrder := zClient.rules.startingOrder("traffic_forwarding_log_rule", func() int {
+			highest := 0
 			list, _ := traffic_log_rules.GetAll(ctx, service)
 			for _, r := range list {
-				if r.Order > trafficForwardingLogStartingOrder {
-					trafficForwardingLogStartingOrder = r.Order
+				if r.Order > highest {
+					highest = r.Order
 				}
 			}
-			if trafficForwardingLogStartingOrder == 0 {
-				trafficForwardingLogStartingOrder = 1
-			}
-		}
-		trafficForwardingLogLock.Unlock()
+			return highest
+		})
 		startWithoutLocking := time.Now()
 
 		intendedOrder := req.Order
@@ -146,7 +220,7 @@ func resourceTrafficForwardingLogRuleRuleCreate(ctx context.Context, d *schema.R
 			// always start rank 7 rules at the next available order after all ranked rules
 			req.Rank = 7
 		}
-		req.Order = trafficForwardingLogStartingOrder
+		req.Order = startingOrder
 		resp, err := traffic_log_rules.Create(ctx, service, &req)
 
 		// Fail immediately if INVALID_INPUT_ARGUMENT is detected
@@ -167,43 +241,29 @@ func resourceTrafficForwardingLogRuleRuleCreate(ctx context.Context, d *schema.R
 		log.Printf("[INFO] Created ztc traffic log forwarding rule request. Took: %s, without locking: %s, ID: %v\n", time.Since(start), time.Since(startWithoutLocking), resp)
 		// Use separate resource type for rank 7 rules to avoid mixing with ranked rules
 		resourceType := "traffic_forwarding_log_rule"
+		zClient.rules.invalidateRuleSlots("traffic_forwarding_log_rule")
+		getCount, updateOrder := logRuleReorder(zClient.rules, service)
 
-		reorderWithBeforeReorder(
+		zClient.rules.reorderWithBeforeReorder(
+			ctx,
 			OrderRule{Order: intendedOrder, Rank: intendedRank},
 			resp.ID,
 			resourceType,
-			func() (int, error) {
-				allRules, err := traffic_log_rules.GetAll(ctx, service)
-				if err != nil {
-					return 0, err
-				}
-				// Count all rules including predefined ones for proper ordering
-				return len(allRules), nil
-			},
-			func(id int, order OrderRule) error {
-				rule, err := traffic_log_rules.Get(ctx, service, id)
-				if err != nil {
-					return err
-				}
-
-				// to avoid the STALE_CONFIGURATION_ERROR
-				rule.LastModifiedTime = 0
-				rule.LastModifiedBy = nil
-				rule.Order = order.Order
-				rule.Rank = order.Rank
-				_, err = traffic_log_rules.Update(ctx, service, id, rule)
-				return err
-			},
+			getCount,
+			updateOrder,
 			nil, // Remove beforeReorder function to avoid adding too many rules to the map
 		)
 
 		d.SetId(strconv.Itoa(resp.ID))
 		_ = d.Set("rule_id", resp.ID)
+		recordIntendedRuleOrder(d)
 
-		markOrderRuleAsDone(resp.ID, resourceType)
-		waitForReorder(resourceType)
+		zClient.rules.markOrderRuleAsDone(resp.ID, resourceType)
+		if err := zClient.rules.waitForReorder(ctx, resourceType, resp.ID); err != nil {
+			return diag.FromErr(fmt.Errorf("error reordering traffic log forwarding rule %d: %w", resp.ID, err))
+		}
 
-		return resourceTrafficForwardingLogRuleRuleRead(ctx, d, meta)
+		return readRuleWithOrderDrift(ctx, d, meta, "ztc_traffic_forwarding_log_rule", resourceTrafficForwardingLogRuleRuleRead)
 	}
 }
 
@@ -226,23 +286,73 @@ func resourceTrafficForwardingLogRuleRuleRead(ctx context.Context, d *schema.Res
 		return diag.FromErr(err)
 	}
 
+	processedDestCountries := make([]string, len(resp.DestCountries))
+	for i, country := range resp.DestCountries {
+		processedDestCountries[i] = strings.TrimPrefix(country, "COUNTRY_")
+	}
+
 	log.Printf("[INFO] Getting traffic log forwarding rule:\n%+v\n", resp)
 
 	d.SetId(fmt.Sprintf("%d", resp.ID))
 	_ = d.Set("name", resp.Name)
 	_ = d.Set("description", resp.Description)
-	_ = d.Set("order", resp.Order)
+	predefined := validatePredefinedLogRules(*resp) != nil
+	order := resp.Order
+	if !predefined {
+		// The slots are listed once and shared by the reads of all the rules of a refresh
+		slots, err := zClient.rules.ruleSlots(ctx, "traffic_forwarding_log_rule", logRuleSlotLister(service))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		order = newRuleOrderSlots(slots).userOrder(resp.Order)
+	}
+	_ = d.Set("order", order)
 	_ = d.Set("rank", resp.Rank)
+	setRuleOrderDrift(d, order, resp.Rank)
 	_ = d.Set("state", resp.State)
+	_ = d.Set("predefined", predefined)
 	_ = d.Set("forward_method", resp.ForwardMethod)
+	_ = d.Set("src_ips", resp.SrcIps)
+	_ = d.Set("dest_addresses", resp.DestAddresses)
+	_ = d.Set("dest_ip_categories", resp.DestIpCategories)
+	_ = d.Set("dest_countries", processedDestCountries)
+	_ = d.Set("res_categories", resp.ResCategories)
 
 	if err := d.Set("locations", flattenIDExtensionsListIDs(resp.Locations)); err != nil {
 		return diag.FromErr(err)
 	}
 
+	if err := d.Set("location_groups", flattenIDExtensionsListIDs(resp.LocationsGroups)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	if err := d.Set("ec_groups", flattenIDExtensionsListIDs(resp.ECGroups)); err != nil {
 		return diag.FromErr(err)
 	}
+
+	if err := d.Set("src_ip_groups", flattenIDExtensionsListIDs(resp.SrcIpGroups)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("dest_ip_groups", flattenIDExtensionsListIDs(resp.DestIpGroups)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("nw_services", flattenIDExtensionsListIDs(resp.NwServices)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("nw_service_groups", flattenIDExtensionsListIDs(resp.NwServiceGroups)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("app_service_groups", flattenIDExtensionsListIDs(resp.AppServiceGroups)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("src_workload_groups", flattenIDExtensionsListIDs(resp.SrcWorkloadGroups)); err != nil {
+		return diag.FromErr(fmt.Errorf("error setting src_workload_groups: %s", err))
+	}
 	if err := d.Set("proxy_gateway", flattenIDNameSet(resp.ProxyGateway)); err != nil {
 		return diag.FromErr(err)
 	}
@@ -272,9 +382,31 @@ func resourceTrafficForwardingLogRuleRuleUpdate(ctx context.Context, d *schema.R
 	if err != nil {
 		log.Printf("[ERROR] error getting all traffic log forwarding rules: %v", err)
 	}
+	for _, r := range existingRules {
+		if r.ID == id && validatePredefinedLogRules(r) != nil {
+			// Predefined rules keep the position assigned by the API and are never reordered
+			req.Order = r.Order
+			req.Rank = r.Rank
+			if _, err := traffic_log_rules.Update(ctx, service, id, &req); err != nil {
+				return diag.FromErr(fmt.Errorf("error updating predefined traffic log forwarding rule %d: %v", id, err))
+			}
+			return resourceTrafficForwardingLogRuleRuleRead(ctx, d, meta)
+		}
+		if r.ID == id && isRuleMetadataOnlyUpdate(d) {
+			// Only the state or metadata changed: keep the current position and skip the reorder
+			log.Printf("[INFO] Updating traffic log forwarding rule %d in place at order %d, rank %d\n", id, r.Order, r.Rank)
+			req.Order = r.Order
+			req.Rank = r.Rank
+			if _, err := traffic_log_rules.Update(ctx, service, id, &req); err != nil {
+				return diag.FromErr(fmt.Errorf("error updating traffic log forwarding rule %d: %v", id, err))
+			}
+			return readRuleWithOrderDrift(ctx, d, meta, "ztc_traffic_forwarding_log_rule", resourceTrafficForwardingLogRuleRuleRead)
+		}
+	}
 	sort.Slice(existingRules, func(i, j int) bool {
 		return existingRules[i].Rank < existingRules[j].Rank || (existingRules[i].Rank == existingRules[j].Rank && existingRules[i].Order < existingRules[j].Order)
 	})
+	recordIntendedRuleOrder(d)
 	intendedOrder := req.Order
 	intendedRank := req.Rank
 	nextAvailableOrder := existingRules[len(existingRules)-1].Order
@@ -294,36 +426,20 @@ func resourceTrafficForwardingLogRuleRuleUpdate(ctx context.Context, d *schema.R
 		return diag.FromErr(fmt.Errorf("error updating resource: %s", err))
 	}
 
-	reorderWithBeforeReorder(OrderRule{Order: intendedOrder, Rank: intendedRank}, req.ID, "traffic_forwarding_log_rule",
-		func() (int, error) {
-			allRules, err := traffic_log_rules.GetAll(ctx, service)
-			if err != nil {
-				return 0, err
-			}
-			// Count all rules including predefined ones for proper ordering
-			return len(allRules), nil
-		},
-		func(id int, order OrderRule) error {
-			rule, err := traffic_log_rules.Get(ctx, service, id)
-			if err != nil {
-				return err
-			}
-
-			// to avoid the STALE_CONFIGURATION_ERROR
-			rule.LastModifiedTime = 0
-			rule.LastModifiedBy = nil
-			rule.Order = order.Order
-			rule.Rank = order.Rank
-			_, err = traffic_log_rules.Update(ctx, service, id, rule)
-			return err
-		},
+	zClient.rules.invalidateRuleSlots("traffic_forwarding_log_rule")
+	getCount, updateOrder := logRuleReorder(zClient.rules, service)
+	zClient.rules.reorderWithBeforeReorder(ctx, OrderRule{Order: intendedOrder, Rank: intendedRank}, req.ID, "traffic_forwarding_log_rule",
+		getCount,
+		updateOrder,
 		nil, // Remove beforeReorder function to avoid adding too many rules to the map
 	)
 
-	markOrderRuleAsDone(req.ID, "traffic_forwarding_log_rule")
-	waitForReorder("traffic_forwarding_log_rule")
+	zClient.rules.markOrderRuleAsDone(req.ID, "traffic_forwarding_log_rule")
+	if err := zClient.rules.waitForReorder(ctx, "traffic_forwarding_log_rule", req.ID); err != nil {
+		return diag.FromErr(fmt.Errorf("error reordering traffic log forwarding rule %d: %w", req.ID, err))
+	}
 
-	return resourceTrafficForwardingLogRuleRuleRead(ctx, d, meta)
+	return readRuleWithOrderDrift(ctx, d, meta, "ztc_traffic_forwarding_log_rule", resourceTrafficForwardingLogRuleRuleRead)
 }
 
 func resourceTrafficForwardingLogRuleRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -335,21 +451,23 @@ func resourceTrafficForwardingLogRuleRuleDelete(ctx context.Context, d *schema.R
 		return diag.FromErr(fmt.Errorf("traffic log forwarding rule ID not set: %v", id))
 	}
 
-	// Retrieve the rule to check if it's a predefined one
-	// rule, err := traffic_log_rules.Get(ctx, service, id)
-	// if err != nil {
-	// 	return diag.FromErr(fmt.Errorf("error retrieving traffic log forwarding rule %d: %v", id, err))
-	// }
-
-	// // Validate if the rule can be deleted
-	// if err := validatePredefinedLogRules(*rule); err != nil {
-	// 	return diag.FromErr(err)
-	// }
+	rule, err := traffic_log_rules.Get(ctx, service, id)
+	if isRuleNotFound(err) {
+		log.Printf("[WARN] Traffic log forwarding rule %d no longer exists", id)
+		d.SetId("")
+		return nil
+	}
+	// Predefined rules cannot be deleted; drop them from state with a warning instead
+	if err := predefinedRuleDeleteCheck(d, err, func() error { return validatePredefinedLogRules(*rule) }); err != nil {
+		d.SetId("")
+		return predefinedRuleDeleteDiagnostics("ztc_traffic_forwarding_log_rule", id, err)
+	}
 
 	log.Printf("[INFO] Deleting traffic log forwarding rule ID: %v", id)
 	if _, err := traffic_log_rules.Delete(ctx, service, id); err != nil {
 		return diag.FromErr(fmt.Errorf("error deleting traffic log forwarding rule %d: %v", id, err))
 	}
+	zClient.rules.invalidateRuleSlots("traffic_forwarding_log_rule")
 
 	d.SetId("")
 	log.Printf("[INFO] Traffic log forwarding rule deleted")
@@ -368,16 +486,28 @@ func expandForwardingLogRule(d *schema.ResourceData) traffic_log_rules.ECTraffic
 	}
 
 	result := traffic_log_rules.ECTrafficLogRules{
-		ID:            id,
-		Name:          d.Get("name").(string),
-		Description:   d.Get("description").(string),
-		Order:         order,
-		Rank:          d.Get("rank").(int),
-		State:         d.Get("state").(string),
-		ForwardMethod: d.Get("forward_method").(string),
-		Locations:     expandIDNameExtensionsSet(d, "locations"),
-		ECGroups:      expandIDNameExtensionsSet(d, "ec_groups"),
-		ProxyGateway:  expandIDNameSet(d, "proxy_gateway"),
+		ID:                id,
+		Name:              d.Get("name").(string),
+		Description:       d.Get("description").(string),
+		Order:             order,
+		Rank:              d.Get("rank").(int),
+		State:             d.Get("state").(string),
+		ForwardMethod:     d.Get("forward_method").(string),
+		SrcIps:            SetToStringList(d, "src_ips"),
+		DestAddresses:     SetToStringList(d, "dest_addresses"),
+		DestIpCategories:  SetToStringList(d, "dest_ip_categories"),
+		DestCountries:     processCountries(SetToStringList(d, "dest_countries")),
+		ResCategories:     SetToStringList(d, "res_categories"),
+		Locations:         expandIDNameExtensionsSet(d, "locations"),
+		LocationsGroups:   expandIDNameExtensionsSet(d, "location_groups"),
+		ECGroups:          expandIDNameExtensionsSet(d, "ec_groups"),
+		SrcIpGroups:       expandIDNameExtensionsSet(d, "src_ip_groups"),
+		DestIpGroups:      expandIDNameExtensionsSet(d, "dest_ip_groups"),
+		NwServices:        expandIDNameExtensionsSet(d, "nw_services"),
+		NwServiceGroups:   expandIDNameExtensionsSet(d, "nw_service_groups"),
+		AppServiceGroups:  expandIDNameExtensionsSet(d, "app_service_groups"),
+		SrcWorkloadGroups: expandIDNameExtensionsSet(d, "src_workload_groups"),
+		ProxyGateway:      expandIDNameSet(d, "proxy_gateway"),
 	}
 	return result
 }
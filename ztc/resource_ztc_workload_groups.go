@@ -0,0 +1,455 @@
+package ztc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/errorx"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zia/services/workloadgroups"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/common"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/forwarding_rules"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/traffic_log_rules"
+)
+
+// Workload groups are read-only in the ZTW API; they are created and changed through the
+// ZIA workload groups API of the same OneAPI tenant. Changes therefore take effect after
+// a ZIA activation, not a ZTC one.
+func resourceWorkloadGroups() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceWorkloadGroupsCreate,
+		ReadContext:   resourceWorkloadGroupsRead,
+		UpdateContext: resourceWorkloadGroupsUpdate,
+		DeleteContext: resourceWorkloadGroupsDelete,
+		Timeouts:      resourceTimeouts(),
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if err := requireOneAPIClient(meta); err != nil {
+				return err
+			}
+			return validateWorkloadExpression(d.Get("expression_json").([]interface{}))
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				if err := requireOneAPIClient(meta); err != nil {
+					return nil, err
+				}
+				zClient := meta.(*Client)
+				service := zClient.Service
+
+				id := d.Id()
+				idInt, parseIDErr := strconv.ParseInt(id, 10, 64)
+				if parseIDErr == nil {
+					_ = d.Set("group_id", idInt)
+				} else {
+					resp, err := workloadgroups.GetByName(ctx, service, id)
+					if err == nil {
+						d.SetId(strconv.Itoa(resp.ID))
+						_ = d.Set("group_id", resp.ID)
+					} else {
+						return []*schema.ResourceData{d}, err
+					}
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"group_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 255),
+				Description:  "The name of the workload group",
+			},
+			"description": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validation.StringLenBetween(0, 10240),
+				StateFunc:        normalizeMultiLineString,
+				DiffSuppressFunc: noChangeInMultiLineText,
+				Description:      "The description of the workload group",
+			},
+			"expression": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The workload group expression as rendered by the API",
+			},
+			"expression_json": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MaxItems:    1,
+				Description: "The workload group expression containing tag types, tags, and their relationships",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"expression_containers": {
+							Type:        schema.TypeList,
+							Required:    true,
+							MinItems:    1,
+							Description: "Groups of tags of the same tag type",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"tag_type": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringInSlice([]string{"ANY", "VPC", "SUBNET", "VM", "ENI", "ATTR"}, false),
+										Description:  "The tag type of the group: `ANY`, `VPC`, `SUBNET`, `VM`, `ENI` or `ATTR`",
+									},
+									"operator": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringInSlice([]string{"AND", "OR"}, false),
+										Description:  "Operator combining this group with the other groups: `AND` or `OR`",
+									},
+									"tag_container": {
+										Type:     schema.TypeList,
+										Required: true,
+										MaxItems: 1,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"operator": {
+													Type:         schema.TypeString,
+													Required:     true,
+													ValidateFunc: validation.StringInSlice([]string{"AND", "OR"}, false),
+													Description:  "Operator combining the tags of the group: `AND` or `OR`",
+												},
+												"tags": {
+													Type:     schema.TypeSet,
+													Required: true,
+													MinItems: 1,
+													Elem: &schema.Resource{
+														Schema: map[string]*schema.Schema{
+															"key": {
+																Type:         schema.TypeString,
+																Required:     true,
+																ValidateFunc: validation.StringIsNotWhiteSpace,
+																Description:  "The tag key",
+															},
+															"value": {
+																Type:        schema.TypeString,
+																Optional:    true,
+																Description: "The tag value",
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceWorkloadGroupsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := requireOneAPIClient(meta); err != nil {
+		return diag.FromErr(err)
+	}
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	req := expandWorkloadGroup(d)
+	log.Printf("[INFO] Creating workload group\n%+v\n", req)
+
+	resp, _, err := workloadgroups.Create(ctx, service, &req)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Created workload group request. ID: %v\n", resp)
+	d.SetId(strconv.Itoa(resp.ID))
+	_ = d.Set("group_id", resp.ID)
+
+	return resourceWorkloadGroupsRead(ctx, d, meta)
+}
+
+func resourceWorkloadGroupsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := requireOneAPIClient(meta); err != nil {
+		return diag.FromErr(err)
+	}
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	id, ok := getIntFromResourceData(d, "group_id")
+	if !ok {
+		return diag.FromErr(fmt.Errorf("no workload group id is set"))
+	}
+	resp, err := workloadgroups.Get(ctx, service, id)
+	if err != nil {
+		if respErr, ok := err.(*errorx.ErrorResponse); ok && respErr.IsObjectNotFound() {
+			log.Printf("[WARN] Removing workload group %s from state because it no longer exists", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] Getting workload group:\n%+v\n", resp)
+
+	d.SetId(fmt.Sprintf("%d", resp.ID))
+	_ = d.Set("group_id", resp.ID)
+	_ = d.Set("name", resp.Name)
+	_ = d.Set("description", resp.Description)
+	_ = d.Set("expression", resp.Expression)
+
+	var prior []interface{}
+	if expr, ok := d.Get("expression_json").([]interface{}); ok && len(expr) > 0 && expr[0] != nil {
+		prior, _ = expr[0].(map[string]interface{})["expression_containers"].([]interface{})
+	}
+	containers := alignWorkloadExpressionContainers(prior, flattenWorkloadGroupExpressionContainers(resp.WorkloadTagExpression))
+	if err := d.Set("expression_json", []interface{}{map[string]interface{}{"expression_containers": containers}}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceWorkloadGroupsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := requireOneAPIClient(meta); err != nil {
+		return diag.FromErr(err)
+	}
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	id, ok := getIntFromResourceData(d, "group_id")
+	if !ok {
+		log.Printf("[ERROR] workload group ID not set: %v\n", id)
+	}
+	log.Printf("[INFO] Updating workload group ID: %v\n", id)
+	req := expandWorkloadGroup(d)
+	if _, err := workloadgroups.Get(ctx, service, id); err != nil {
+		if respErr, ok := err.(*errorx.ErrorResponse); ok && respErr.IsObjectNotFound() {
+			d.SetId("")
+			return nil
+		}
+	}
+	if _, _, err := workloadgroups.Update(ctx, service, id, &req); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceWorkloadGroupsRead(ctx, d, meta)
+}
+
+func resourceWorkloadGroupsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := requireOneAPIClient(meta); err != nil {
+		return diag.FromErr(err)
+	}
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	id, ok := getIntFromResourceData(d, "group_id")
+	if !ok {
+		log.Printf("[ERROR] workload group ID not set: %v\n", id)
+	}
+	log.Printf("[INFO] Deleting workload group ID: %v\n", (d.Id()))
+	err := DetachRuleIDNameExtensions(
+		ctx,
+		zClient,
+		id,
+		"WorkloadGroups",
+		func(r *forwarding_rules.ForwardingRules) []common.IDNameExtensions {
+			return r.SrcWorkloadGroups
+		},
+		func(r *forwarding_rules.ForwardingRules, ids []common.IDNameExtensions) {
+			r.SrcWorkloadGroups = ids
+		},
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = DetachLogRuleIDNameExtensions(
+		ctx,
+		zClient,
+		id,
+		"WorkloadGroups",
+		func(r *traffic_log_rules.ECTrafficLogRules) []common.IDNameExtensions {
+			return r.SrcWorkloadGroups
+		},
+		func(r *traffic_log_rules.ECTrafficLogRules, ids []common.IDNameExtensions) {
+			r.SrcWorkloadGroups = ids
+		},
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, err := workloadgroups.Delete(ctx, service, id); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId("")
+	log.Printf("[INFO] workload group deleted")
+
+	return nil
+}
+
+// requireOneAPIClient rejects the legacy API framework, which has no access to the ZIA
+// workload groups API.
+func requireOneAPIClient(meta interface{}) error {
+	if zClient, ok := meta.(*Client); ok && zClient.legacy {
+		return fmt.Errorf("ztc_workload_groups requires OneAPI authentication: workload groups are managed through the ZIA API, which the legacy API framework (use_legacy_client) cannot reach. Use the data source ztc_workload_groups to reference existing groups instead")
+	}
+	return nil
+}
+
+func expandWorkloadGroup(d *schema.ResourceData) workloadgroups.WorkloadGroup {
+	id, _ := getIntFromResourceData(d, "group_id")
+	return workloadgroups.WorkloadGroup{
+		ID:                    id,
+		Name:                  d.Get("name").(string),
+		Description:           d.Get("description").(string),
+		WorkloadTagExpression: expandWorkloadTagExpression(d.Get("expression_json").([]interface{})),
+	}
+}
+
+func expandWorkloadTagExpression(list []interface{}) workloadgroups.WorkloadTagExpression {
+	var expression workloadgroups.WorkloadTagExpression
+	if len(list) == 0 || list[0] == nil {
+		return expression
+	}
+	containers, _ := list[0].(map[string]interface{})["expression_containers"].([]interface{})
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		expanded := workloadgroups.ExpressionContainer{
+			TagType:  container["tag_type"].(string),
+			Operator: container["operator"].(string),
+		}
+		if tagContainers, ok := container["tag_container"].([]interface{}); ok && len(tagContainers) > 0 && tagContainers[0] != nil {
+			tagContainer := tagContainers[0].(map[string]interface{})
+			expanded.TagContainer.Operator = tagContainer["operator"].(string)
+			if tags, ok := tagContainer["tags"].(*schema.Set); ok {
+				for _, t := range sortedWorkloadTags(tags.List()) {
+					tag := t.(map[string]interface{})
+					expanded.TagContainer.Tags = append(expanded.TagContainer.Tags, workloadgroups.Tags{
+						Key:   tag["key"].(string),
+						Value: tag["value"].(string),
+					})
+				}
+			}
+		}
+		expression.ExpressionContainers = append(expression.ExpressionContainers, expanded)
+	}
+	return expression
+}
+
+func flattenWorkloadGroupExpressionContainers(expression workloadgroups.WorkloadTagExpression) []interface{} {
+	containers := make([]interface{}, 0, len(expression.ExpressionContainers))
+	for _, container := range expression.ExpressionContainers {
+		tags := make([]interface{}, 0, len(container.TagContainer.Tags))
+		for _, tag := range container.TagContainer.Tags {
+			tags = append(tags, map[string]interface{}{
+				"key":   tag.Key,
+				"value": tag.Value,
+			})
+		}
+		containers = append(containers, map[string]interface{}{
+			"tag_type": container.TagType,
+			"operator": container.Operator,
+			"tag_container": []interface{}{map[string]interface{}{
+				"operator": container.TagContainer.Operator,
+				"tags":     tags,
+			}},
+		})
+	}
+	return containers
+}
+
+// sortedWorkloadTags orders tags by key and value so that the request sent to the API does
+// not depend on the iteration order of the tags set.
+func sortedWorkloadTags(tags []interface{}) []interface{} {
+	sorted := append([]interface{}{}, tags...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].(map[string]interface{}), sorted[j].(map[string]interface{})
+		if a["key"] != b["key"] {
+			return a["key"].(string) < b["key"].(string)
+		}
+		return fmt.Sprint(a["value"]) < fmt.Sprint(b["value"])
+	})
+	return sorted
+}
+
+// workloadExpressionContainerKey identifies an expression container by its tag type and
+// tags, independently of the order in which the tags are listed.
+func workloadExpressionContainerKey(container interface{}) string {
+	m, ok := container.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	var tags []string
+	if tagContainers, ok := m["tag_container"].([]interface{}); ok && len(tagContainers) > 0 && tagContainers[0] != nil {
+		var list []interface{}
+		switch t := tagContainers[0].(map[string]interface{})["tags"].(type) {
+		case *schema.Set:
+			list = t.List()
+		case []interface{}:
+			list = t
+		}
+		for _, tag := range list {
+			if tm, ok := tag.(map[string]interface{}); ok {
+				tags = append(tags, fmt.Sprintf("%v=%v", tm["key"], tm["value"]))
+			}
+		}
+	}
+	sort.Strings(tags)
+	return fmt.Sprintf("%v|%s", m["tag_type"], strings.Join(tags, ","))
+}
+
+// validateWorkloadExpression rejects expressions that repeat the same group of tags.
+func validateWorkloadExpression(list []interface{}) error {
+	if len(list) == 0 || list[0] == nil {
+		return nil
+	}
+	containers, _ := list[0].(map[string]interface{})["expression_containers"].([]interface{})
+	seen := map[string]int{}
+	for i, c := range containers {
+		key := workloadExpressionContainerKey(c)
+		if j, exists := seen[key]; exists {
+			return fmt.Errorf("expression_containers %d and %d have the same tag_type and tags", j, i)
+		}
+		seen[key] = i
+	}
+	return nil
+}
+
+// alignWorkloadExpressionContainers returns the containers read from the API in the order
+// of the containers previously in state, so that the API returning them in a different
+// order does not produce a diff. Containers that are new are appended in API order.
+func alignWorkloadExpressionContainers(prior, current []interface{}) []interface{} {
+	position := map[string]int{}
+	for i, c := range prior {
+		key := workloadExpressionContainerKey(c)
+		if _, exists := position[key]; !exists {
+			position[key] = i
+		}
+	}
+	aligned := append([]interface{}{}, current...)
+	sort.SliceStable(aligned, func(i, j int) bool {
+		pi, iKnown := position[workloadExpressionContainerKey(aligned[i])]
+		pj, jKnown := position[workloadExpressionContainerKey(aligned[j])]
+		switch {
+		case iKnown && jKnown:
+			return pi < pj
+		default:
+			return iKnown && !jKnown
+		}
+	})
+	return aligned
+}
@@ -0,0 +1,87 @@
+package ztc
+
+import "testing"
+
+func TestRequireOneAPIClient(t *testing.T) {
+	if err := requireOneAPIClient(&Client{}); err != nil {
+		t.Errorf("unexpected error for a OneAPI client: %v", err)
+	}
+	if err := requireOneAPIClient(&Client{legacy: true}); err == nil {
+		t.Error("expected the legacy client to be rejected")
+	}
+}
+
+func workloadContainer(tagType string, tags ...string) map[string]interface{} {
+	list := make([]interface{}, 0, len(tags))
+	for _, key := range tags {
+		list = append(list, map[string]interface{}{"key": key, "value": "v"})
+	}
+	return map[string]interface{}{
+		"tag_type":      tagType,
+		"operator":      "AND",
+		"tag_container": []interface{}{map[string]interface{}{"operator": "OR", "tags": list}},
+	}
+}
+
+func TestAlignWorkloadExpressionContainers(t *testing.T) {
+	prior := []interface{}{
+		workloadContainer("ATTR", "env", "team"),
+		workloadContainer("VPC", "vpc-1"),
+	}
+	// The API returns the containers in a different order and the tags reversed
+	current := []interface{}{
+		workloadContainer("ENI", "eni-1"),
+		workloadContainer("VPC", "vpc-1"),
+		workloadContainer("ATTR", "team", "env"),
+	}
+
+	aligned := alignWorkloadExpressionContainers(prior, current)
+	var got []string
+	for _, c := range aligned {
+		got = append(got, c.(map[string]interface{})["tag_type"].(string))
+	}
+	want := []string{"ATTR", "VPC", "ENI"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("aligned order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestValidateWorkloadExpression(t *testing.T) {
+	valid := []interface{}{map[string]interface{}{"expression_containers": []interface{}{
+		workloadContainer("ATTR", "env"),
+		workloadContainer("VPC", "vpc-1"),
+	}}}
+	if err := validateWorkloadExpression(valid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	duplicate := []interface{}{map[string]interface{}{"expression_containers": []interface{}{
+		workloadContainer("ATTR", "env", "team"),
+		workloadContainer("ATTR", "team", "env"),
+	}}}
+	if err := validateWorkloadExpression(duplicate); err == nil {
+		t.Fatal("expected an error for duplicate expression containers")
+	}
+}
+
+func TestSortedWorkloadTags(t *testing.T) {
+	tags := []interface{}{
+		map[string]interface{}{"key": "team", "value": "net"},
+		map[string]interface{}{"key": "env", "value": "prod"},
+		map[string]interface{}{"key": "env", "value": "dev"},
+	}
+	sorted := sortedWorkloadTags(tags)
+	var got []string
+	for _, tag := range sorted {
+		m := tag.(map[string]interface{})
+		got = append(got, m["key"].(string)+"="+m["value"].(string))
+	}
+	want := []string{"env=dev", "env=prod", "team=net"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sorted tags = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package ztc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// State upgrades let a resource change the shape of an attribute in a minor release.
+// When a schema change is not compatible with the stored state, bump the resource's
+// SchemaVersion, keep the previous schema as resource<Name>V<N>() and add a
+// stateUpgrader that rewrites the raw state from version N to N+1. Each upgrader is
+// covered by a golden state file in test-fixtures/state.
+
+// stateUpgrader returns the upgrader of state stored with schema version from of the
+// prior resource schema.
+func stateUpgrader(from int, prior *schema.Resource, upgrade schema.StateUpgradeFunc) schema.StateUpgrader {
+	return schema.StateUpgrader{
+		Version: from,
+		Type:    prior.CoreConfigSchema().ImpliedType(),
+		Upgrade: upgrade,
+	}
+}
+
+// upgradeMapToBlocks moves the string map stored in from to a list of blocks in to,
+// one block per entry sorted by key, with the key in keyAttr and the value in
+// valueAttr.
+func upgradeMapToBlocks(rawState map[string]interface{}, from, to, keyAttr, valueAttr string) {
+	v, ok := rawState[from]
+	if !ok {
+		return
+	}
+	delete(rawState, from)
+	m, _ := v.(map[string]interface{})
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	blocks := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		blocks = append(blocks, map[string]interface{}{
+			keyAttr:   k,
+			valueAttr: fmt.Sprint(m[k]),
+		})
+	}
+	rawState[to] = blocks
+}
+
+// resourceActivationStatusV0 is the ztc_activation_status schema before
+// admin_status_map became the admin_status list.
+func resourceActivationStatusV0() *schema.Resource {
+	return &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+		Schema: map[string]*schema.Schema{
+			"org_edit_status":          {Type: schema.TypeString, Optional: true, ForceNew: true},
+			"org_last_activate_status": {Type: schema.TypeString, Optional: true, ForceNew: true},
+			"admin_status_map": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"admin_activate_status": {Type: schema.TypeString, Optional: true, ForceNew: true},
+			"comment":               {Type: schema.TypeString, Optional: true, ForceNew: true},
+			"change_ticket":         {Type: schema.TypeString, Optional: true, ForceNew: true},
+			"change_summary_file":   {Type: schema.TypeString, Optional: true, ForceNew: true},
+			"change_summary":        {Type: schema.TypeString, Computed: true},
+		},
+	}
+}
+
+// resourceActivationStatusStateUpgradeV0 moves admin_status_map, keyed by admin ID, to
+// the admin_status list.
+func resourceActivationStatusStateUpgradeV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+	upgradeMapToBlocks(rawState, "admin_status_map", "admin_status", "admin_id", "status")
+	return rawState, nil
+}
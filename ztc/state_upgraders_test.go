@@ -0,0 +1,118 @@
+package ztc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// readStateFixture loads a golden state from test-fixtures/state and checks that it
+// conforms to the schema of the version it was stored with.
+func readStateFixture(t *testing.T, name string, ty cty.Type) map[string]interface{} {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("test-fixtures", "state", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctyjson.Unmarshal(raw, ty); err != nil {
+		t.Fatalf("%s does not match its schema: %v", name, err)
+	}
+	var state map[string]interface{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+func testStateUpgrade(t *testing.T, r *schema.Resource, from int, prior, want string) {
+	t.Helper()
+	var upgrader *schema.StateUpgrader
+	for i := range r.StateUpgraders {
+		if r.StateUpgraders[i].Version == from {
+			upgrader = &r.StateUpgraders[i]
+		}
+	}
+	if upgrader == nil {
+		t.Fatalf("no state upgrader from version %d", from)
+	}
+
+	state := readStateFixture(t, prior, upgrader.Type)
+	got, err := upgrader.Upgrade(context.Background(), state, &Client{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The upgraded state must also be valid for the version the upgrader produces.
+	next := r.CoreConfigSchema().ImpliedType()
+	if from+1 < r.SchemaVersion {
+		next = r.StateUpgraders[from+1].Type
+	}
+	expected := readStateFixture(t, want, next)
+	encoded, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctyjson.Unmarshal(encoded, next); err != nil {
+		t.Fatalf("upgraded state does not match the version %d schema: %v", from+1, err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("upgraded state = %s, want the content of %s", encoded, want)
+	}
+}
+
+// TestStateUpgraders checks that every resource that bumped its SchemaVersion has one
+// state upgrader per prior version, in order.
+func TestStateUpgraders(t *testing.T) {
+	for name, r := range ZTCProvider().ResourcesMap {
+		if len(r.StateUpgraders) != r.SchemaVersion {
+			t.Errorf("%s: schema version %d has %d state upgraders", name, r.SchemaVersion, len(r.StateUpgraders))
+		}
+		for i, upgrader := range r.StateUpgraders {
+			if upgrader.Version != i {
+				t.Errorf("%s: state upgrader %d upgrades version %d", name, i, upgrader.Version)
+			}
+		}
+	}
+}
+
+func TestResourceActivationStatusStateUpgradeV0(t *testing.T) {
+	testStateUpgrade(t, resourceActivationStatus(), 0, "activation_status_v0.json", "activation_status_v1.json")
+}
+
+func TestUpgradeMapToBlocks(t *testing.T) {
+	cases := map[string]struct {
+		state map[string]interface{}
+		want  map[string]interface{}
+	}{
+		"entries": {
+			state: map[string]interface{}{"admin_status_map": map[string]interface{}{"2": "ADM_EDITING", "1": "ADM_ACTV_DONE"}},
+			want: map[string]interface{}{"admin_status": []interface{}{
+				map[string]interface{}{"admin_id": "1", "status": "ADM_ACTV_DONE"},
+				map[string]interface{}{"admin_id": "2", "status": "ADM_EDITING"},
+			}},
+		},
+		"null": {
+			state: map[string]interface{}{"admin_status_map": nil},
+			want:  map[string]interface{}{"admin_status": []interface{}{}},
+		},
+		"missing": {
+			state: map[string]interface{}{},
+			want:  map[string]interface{}{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			upgradeMapToBlocks(tc.state, "admin_status_map", "admin_status", "admin_id", "status")
+			if !reflect.DeepEqual(tc.state, tc.want) {
+				t.Errorf("got %v, want %v", tc.state, tc.want)
+			}
+		})
+	}
+}
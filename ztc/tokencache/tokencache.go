@@ -0,0 +1,288 @@
+// Package tokencache keeps OneAPI access tokens in an encrypted on-disk cache, so that
+// provider runs started in parallel or in quick succession reuse a token instead of
+// each doing an OAuth exchange with the identity provider.
+package tokencache
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRefreshMargin is how long before its expiry a cached token stops being
+	// used, leaving the SDK enough time to use it before it has to refresh.
+	DefaultRefreshMargin = 10 * time.Minute
+	// DefaultLockTimeout bounds the wait for another process fetching the same token.
+	DefaultLockTimeout = 30 * time.Second
+
+	// staleLockAge is the age after which a lock left by a crashed process is broken.
+	staleLockAge     = 2 * time.Minute
+	lockPollInterval = 50 * time.Millisecond
+)
+
+// Key identifies the OneAPI client a cached token belongs to.
+type Key struct {
+	ClientID     string
+	VanityDomain string
+	Cloud        string
+}
+
+// NewKey returns the Key of a OneAPI client with the whitespace around its fields
+// trimmed, so the provider and the CLI find the same cache entry for a client.
+func NewKey(clientID, vanityDomain, cloud string) Key {
+	return Key{
+		ClientID:     strings.TrimSpace(clientID),
+		VanityDomain: strings.TrimSpace(vanityDomain),
+		Cloud:        strings.TrimSpace(cloud),
+	}
+}
+
+func (k Key) fileName() string {
+	sum := sha256.Sum256([]byte(k.ClientID + "\n" + k.VanityDomain + "\n" + k.Cloud))
+	return hex.EncodeToString(sum[:])
+}
+
+// entry is a cache file. The token response is encrypted with AES-GCM under a key
+// derived from the client credentials, so reading it takes the same secret as
+// requesting a new token.
+type entry struct {
+	ExpiresAt  time.Time `json:"expires_at"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+}
+
+// Transport serves OneAPI token requests from the cache while the cached token is
+// valid for longer than RefreshMargin, and stores the tokens it fetches through Next.
+// Other requests go straight to Next. Concurrent processes fetching the token of the
+// same Key wait for each other through a lock file, so only one of them asks the
+// identity provider.
+type Transport struct {
+	// Dir is the cache directory. It is created with owner-only permissions.
+	Dir string
+	Key Key
+	// Secret is the client secret or private key of Key. The cache is not used
+	// without it.
+	Secret        string
+	RefreshMargin time.Duration
+	LockTimeout   time.Duration
+	Next          http.RoundTripper
+
+	now func() time.Time
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if t.Dir == "" || t.Secret == "" || req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, "/oauth2/v1/token") {
+		return next.RoundTrip(req)
+	}
+
+	if err := os.MkdirAll(t.Dir, 0o700); err != nil {
+		log.Printf("[WARN] Token cache disabled: %v", err)
+		return next.RoundTrip(req)
+	}
+	path := filepath.Join(t.Dir, t.Key.fileName())
+	unlock, err := lock(req.Context(), path+".lock", t.lockTimeout())
+	if err != nil {
+		log.Printf("[WARN] Token cache not used: %v", err)
+		return next.RoundTrip(req)
+	}
+	defer unlock()
+
+	if body, ok := t.load(path); ok {
+		log.Printf("[DEBUG] Using cached OneAPI token for client %s", t.Key.ClientID)
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return cachedResponse(req, body), nil
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err := t.store(path, body); err != nil {
+		log.Printf("[WARN] Failed to cache the OneAPI token: %v", err)
+	}
+	return resp, nil
+}
+
+// load returns the cached token response, with expires_in set to the remaining
+// lifetime of the token, when it is valid for longer than the refresh margin.
+func (t *Transport) load(path string) ([]byte, bool) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var e entry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, false
+	}
+	remaining := e.ExpiresAt.Sub(t.clock())
+	if remaining <= t.refreshMargin() {
+		return nil, false
+	}
+	aead, err := t.aead()
+	if err != nil {
+		return nil, false
+	}
+	plain, err := aead.Open(nil, e.Nonce, e.Ciphertext, []byte(t.Key.fileName()))
+	if err != nil {
+		// Written with other credentials, for example after a secret rotation
+		return nil, false
+	}
+	var token map[string]interface{}
+	if err := json.Unmarshal(plain, &token); err != nil {
+		return nil, false
+	}
+	token["expires_in"] = int64(remaining / time.Second)
+	body, err := json.Marshal(token)
+	if err != nil {
+		return nil, false
+	}
+	return body, true
+}
+
+// store encrypts a token response and writes it atomically to path.
+func (t *Transport) store(path string, body []byte) error {
+	var token struct {
+		AccessToken string      `json:"access_token"`
+		ExpiresIn   json.Number `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return err
+	}
+	seconds, err := strconv.ParseInt(token.ExpiresIn.String(), 10, 64)
+	if token.AccessToken == "" || err != nil || seconds <= 0 {
+		return errors.New("the token response has no access token or expiry")
+	}
+
+	aead, err := t.aead()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(entry{
+		ExpiresAt:  t.clock().Add(time.Duration(seconds) * time.Second),
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, body, []byte(t.Key.fileName())),
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(t.Dir, ".token-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (t *Transport) aead() (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, []byte(t.Secret), []byte(t.Key.fileName()), "ztc token cache", 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (t *Transport) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+func (t *Transport) refreshMargin() time.Duration {
+	if t.RefreshMargin > 0 {
+		return t.RefreshMargin
+	}
+	return DefaultRefreshMargin
+}
+
+func (t *Transport) lockTimeout() time.Duration {
+	if t.LockTimeout > 0 {
+		return t.LockTimeout
+	}
+	return DefaultLockTimeout
+}
+
+func cachedResponse(req *http.Request, body []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// lock takes the lock file at path, waiting up to timeout for another holder. Lock
+// files older than staleLockAge are left by crashed processes and are removed.
+func lock(ctx context.Context, path string, timeout time.Duration) (func(), error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(path)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for the token cache lock %s: %w", path, ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package tokencache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenServer counts the token requests it answers with a one hour token.
+func tokenServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth2/v1/token" {
+			fmt.Fprint(w, `{"path":"`+r.URL.Path+`"}`)
+			return
+		}
+		n := atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"token_type":"Bearer","expires_in":3600,"access_token":"token-%d"}`, n)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func requestToken(t *testing.T, client *http.Client, url string) map[string]interface{} {
+	t.Helper()
+	resp, err := client.Post(url+"/oauth2/v1/token", "application/x-www-form-urlencoded", strings.NewReader("grant_type=client_credentials"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var token map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func newTransport(dir, secret string, now func() time.Time) *Transport {
+	return &Transport{
+		Dir:    dir,
+		Key:    NewKey("client", "acme", "beta"),
+		Secret: secret,
+		now:    now,
+	}
+}
+
+func TestNewKey(t *testing.T) {
+	want := Key{ClientID: "client", VanityDomain: "acme", Cloud: "beta"}
+	if got := NewKey(" client\n", "acme ", "\tbeta"); got != want {
+		t.Errorf("NewKey = %+v, want %+v", got, want)
+	}
+	if NewKey("client", "acme", "").fileName() != NewKey("client", "acme", " ").fileName() {
+		t.Error("an unset and a blank cloud must share the cache entry")
+	}
+}
+
+func TestTransportReusesToken(t *testing.T) {
+	server, calls := tokenServer(t)
+	dir := t.TempDir()
+	start := time.Now()
+
+	first := requestToken(t, &http.Client{Transport: newTransport(dir, "s3cr3t", func() time.Time { return start })}, server.URL)
+	// A second process, 20 minutes later
+	second := requestToken(t, &http.Client{Transport: newTransport(dir, "s3cr3t", func() time.Time { return start.Add(20 * time.Minute) })}, server.URL)
+
+	if *calls != 1 {
+		t.Fatalf("expected a single token request, got %d", *calls)
+	}
+	if first["access_token"] != "token-1" || second["access_token"] != "token-1" {
+		t.Fatalf("expected the cached token, got %v and %v", first["access_token"], second["access_token"])
+	}
+	if second["expires_in"] != float64(40*60) {
+		t.Fatalf("expected expires_in to be the remaining lifetime, got %v", second["expires_in"])
+	}
+}
+
+func TestTransportRefreshMargin(t *testing.T) {
+	server, calls := tokenServer(t)
+	dir := t.TempDir()
+	start := time.Now()
+
+	requestToken(t, &http.Client{Transport: newTransport(dir, "s3cr3t", func() time.Time { return start })}, server.URL)
+	late := requestToken(t, &http.Client{Transport: newTransport(dir, "s3cr3t", func() time.Time { return start.Add(55 * time.Minute) })}, server.URL)
+
+	if *calls != 2 || late["access_token"] != "token-2" {
+		t.Fatalf("expected a new token within the refresh margin, got %d requests and %v", *calls, late["access_token"])
+	}
+}
+
+func TestTransportEncryptsTokens(t *testing.T) {
+	server, calls := tokenServer(t)
+	dir := t.TempDir()
+
+	requestToken(t, &http.Client{Transport: newTransport(dir, "s3cr3t", nil)}, server.URL)
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected a single cache file, got %v, %v", files, err)
+	}
+	raw, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "token-1") {
+		t.Fatal("the cache file contains the token in clear text")
+	}
+	if info, _ := os.Stat(files[0]); info.Mode().Perm()&0o077 != 0 {
+		t.Fatalf("the cache file is readable by others: %v", info.Mode())
+	}
+
+	// Another secret cannot read the entry and fetches its own token
+	other := requestToken(t, &http.Client{Transport: newTransport(dir, "rotated", nil)}, server.URL)
+	if *calls != 2 || other["access_token"] != "token-2" {
+		t.Fatalf("expected a new token with another secret, got %d requests and %v", *calls, other["access_token"])
+	}
+}
+
+func TestTransportPassesThrough(t *testing.T) {
+	server, calls := tokenServer(t)
+
+	// Without a secret, nothing is cached
+	client := &http.Client{Transport: newTransport(t.TempDir(), "", nil)}
+	requestToken(t, client, server.URL)
+	requestToken(t, client, server.URL)
+	if *calls != 2 {
+		t.Fatalf("expected every token request to reach the server, got %d", *calls)
+	}
+
+	// Other requests are not cached
+	client = &http.Client{Transport: newTransport(t.TempDir(), "s3cr3t", nil)}
+	resp, err := client.Get(server.URL + "/ztw/api/v1/ecRules/ecRdr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "/ztw/api/v1/ecRules/ecRdr") {
+		t.Fatalf("unexpected response %s", body)
+	}
+}
+
+func TestTransportConcurrentProcesses(t *testing.T) {
+	server, calls := tokenServer(t)
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each transport stands in for a separate provider process
+			token := requestToken(t, &http.Client{Transport: newTransport(dir, "s3cr3t", nil)}, server.URL)
+			if token["access_token"] != "token-1" {
+				t.Errorf("expected the shared token, got %v", token["access_token"])
+			}
+		}()
+	}
+	wg.Wait()
+	if atomic.LoadInt32(calls) != 1 {
+		t.Fatalf("expected a single token request, got %d", *calls)
+	}
+}
+
+func TestLockBreaksStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.lock")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleLockAge)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := lock(t.Context(), path, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+
+	// A fresh lock held by another process makes the caller wait and give up
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lock(t.Context(), path, 200*time.Millisecond); err == nil {
+		t.Fatal("expected the lock wait to time out")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/errorx"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/common"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/forwarding_rules"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/ztw/services/policy_management/traffic_log_rules"
 )
 
 func intPtr(n int) *int {
@@ -177,7 +178,11 @@ func DetachRuleIDNameExtensions(ctx context.Context, client *Client, id int, res
 		}
 		if shouldUpdate {
 			setResources(&rule, ids)
-			time.Sleep(time.Second * 5)
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("detaching %s %d from forwarding rules: %w", resource, id, ctx.Err())
+			case <-time.After(time.Second * 5):
+			}
 			_, err = forwarding_rules.Get(ctx, service, rule.ID)
 			if err == nil {
 				_, err = forwarding_rules.Update(ctx, service, rule.ID, &rule)
@@ -189,3 +194,48 @@ func DetachRuleIDNameExtensions(ctx context.Context, client *Client, id int, res
 	}
 	return nil
 }
+
+// DetachLogRuleIDNameExtensions removes the object with the given ID from the log
+// forwarding rules that reference it, so that the object can be deleted.
+func DetachLogRuleIDNameExtensions(ctx context.Context, client *Client, id int, resource string, getResources func(*traffic_log_rules.ECTrafficLogRules) []common.IDNameExtensions, setResources func(*traffic_log_rules.ECTrafficLogRules, []common.IDNameExtensions)) error {
+	service := client.Service
+
+	log.Printf("[INFO] Detaching log forwarding rules from %s: %d\n", resource, id)
+	rules, err := traffic_log_rules.GetAll(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	for _, listed := range rules {
+		if !containsIDNameExtension(getResources(&listed), id) {
+			continue
+		}
+		rule, err := traffic_log_rules.Get(ctx, service, listed.ID)
+		if err != nil {
+			continue
+		}
+		ids := []common.IDNameExtensions{}
+		for _, r := range getResources(rule) {
+			if r.ID != id {
+				ids = append(ids, r)
+			}
+		}
+		setResources(rule, ids)
+		// to avoid the STALE_CONFIGURATION_ERROR
+		rule.LastModifiedTime = 0
+		rule.LastModifiedBy = nil
+		if _, err := traffic_log_rules.Update(ctx, service, rule.ID, rule); err != nil {
+			return fmt.Errorf("detaching %s %d from log forwarding rule %d: %w", resource, id, rule.ID, err)
+		}
+	}
+	return nil
+}
+
+func containsIDNameExtension(list []common.IDNameExtensions, id int) bool {
+	for _, item := range list {
+		if item.ID == id {
+			return true
+		}
+	}
+	return false
+}
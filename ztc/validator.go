@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/terraform-provider-ztc/ztc/common/multiline"
 )
 
 // Validate Location Management Options
@@ -72,18 +73,11 @@ func normalizeMultiLineString(val interface{}) string {
 		return ""
 	}
 
-	// Trim leading/trailing whitespace for consistency
-	str = strings.TrimSpace(str)
-
-	// Ensure uniform indentation by trimming each line
-	lines := strings.Split(str, "\n")
-	for i := range lines {
-		lines[i] = strings.TrimSpace(lines[i])
-	}
+	// Trim the text and each of its lines so indentation does not matter
+	str = multiline.TrimLines(str)
 
 	// Escape Terraform variable interpolation (`$` → `$$`)
-	escapedStr := strings.Join(lines, "\n")
-	escapedStr = strings.ReplaceAll(escapedStr, "$", "$$")
+	escapedStr := strings.ReplaceAll(str, "$", "$$")
 
 	// Ensure the final newline to match Terraform formatting
 	return escapedStr + "\n"